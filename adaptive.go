@@ -0,0 +1,120 @@
+package scrapify
+
+import (
+	"context"
+	"sync"
+)
+
+// adaptiveErrorRate is the fraction of failed calls in a window above which WithAdaptiveConcurrency halves the worker limit.
+const adaptiveErrorRate = 0.1
+
+// WithAdaptiveConcurrency adjusts the number of pages and item URLs processed at once between minWorkers and maxWorkers,
+// to protect the whole crawl from a throttle hitting every host at once, such as one on the IP of the crawler, which per-host
+// backoffs do not address. The limit starts at maxWorkers and follows an AIMD scheme over windows of as many calls as the
+// current limit: it is halved when more than 10% of the GetUrls and GetData calls of a window fail, and raised by one when
+// none does. Every attempt counts, retries included, except those failing with a permanent error or after cancellation.
+// It applies on top of WithConcurrency. The current limit is reported in Stats().Concurrency.
+func WithAdaptiveConcurrency(minWorkers, maxWorkers int) Option {
+	return func(o *options) {
+		o.adaptiveMin = max(minWorkers, 1)
+		o.adaptiveMax = max(maxWorkers, o.adaptiveMin)
+	}
+}
+
+// adaptiveLimit bounds the number of workers by a limit adjusted to the error rate of their calls.
+type adaptiveLimit struct {
+	mu       sync.Mutex    // Guards the fields below.
+	min, max int           // Bounds of the limit.
+	limit    int           // Current maximum number of workers.
+	inFlight int           // Workers running.
+	calls    int           // Calls of the current window.
+	failures int           // Failed calls of the current window.
+	free     chan struct{} // Wakes up the dispatcher when a worker may be free.
+}
+
+// newAdaptiveLimit creates the adaptive limit of WithAdaptiveConcurrency, or returns nil when it is not configured.
+func newAdaptiveLimit(o options) *adaptiveLimit {
+	if o.adaptiveMax <= 0 {
+		return nil
+	}
+	return &adaptiveLimit{min: o.adaptiveMin, max: o.adaptiveMax, limit: o.adaptiveMax, free: make(chan struct{}, 1)}
+}
+
+// acquire blocks until a worker is free under the current limit, or until the context is done.
+func (a *adaptiveLimit) acquire(ctx context.Context) error {
+	for {
+		a.mu.Lock()
+		if a.inFlight < a.limit {
+			a.inFlight++
+			a.mu.Unlock()
+			return nil
+		}
+		a.mu.Unlock()
+
+		select {
+		case <-a.free:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release frees the worker of a finished work.
+func (a *adaptiveLimit) release() {
+	a.mu.Lock()
+	a.inFlight--
+	a.mu.Unlock()
+
+	a.signal()
+}
+
+// record counts the outcome of a call and adjusts the limit once the window is complete.
+func (a *adaptiveLimit) record(failed bool) {
+	a.mu.Lock()
+	a.calls++
+	if failed {
+		a.failures++
+	}
+	if a.calls < a.limit {
+		a.mu.Unlock()
+		return
+	}
+
+	raised := false
+	switch {
+	case float64(a.failures) > adaptiveErrorRate*float64(a.calls):
+		a.limit = max(a.limit/2, a.min)
+	case a.failures == 0 && a.limit < a.max:
+		a.limit++
+		raised = true
+	}
+	a.calls, a.failures = 0, 0
+	a.mu.Unlock()
+
+	if raised {
+		a.signal()
+	}
+}
+
+// current returns the current limit.
+func (a *adaptiveLimit) current() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.limit
+}
+
+// signal wakes up the dispatcher, if it is waiting for a worker.
+func (a *adaptiveLimit) signal() {
+	select {
+	case a.free <- struct{}{}:
+	default:
+	}
+}
+
+// adapt records the outcome of a call with the adaptive limit, if enabled. Cancelled calls and permanent errors are not failures.
+func (s *Scraper[T]) adapt(ctx context.Context, err error) {
+	if s.adaptive != nil && ctx.Err() == nil {
+		s.adaptive.record(err != nil && !permanent(err))
+	}
+}
@@ -0,0 +1,83 @@
+// Package browser adapts a headless browser to scrapify.IScraper, for sites rendering their content with JavaScript
+// where a plain HTTP fetch only returns an empty shell.
+//
+// The package does not depend on any browser automation library. A backend implements the Browser interface in its own
+// module, so the heavy dependency stays out of scrapify: the browser/chromedp module implements it with a headless Chrome
+// driven by chromedp, and other backends can be written in the application the same way.
+package browser
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+// Browser loads pages in a headless browser.
+// Implementations must be safe for concurrent use, since the Scraper renders pages from many goroutines.
+type Browser interface {
+	// Render loads the URL, waits until every selector of waitFor is visible, and returns the rendered page.
+	// It must return when the context is done.
+	Render(ctx context.Context, url string, waitFor []string) (*Page, error)
+}
+
+// Page is a page rendered by a Browser.
+type Page struct {
+	URL  string // Final URL of the page, after redirects and client-side navigation.
+	HTML string // Rendered document, serialized as HTML.
+}
+
+// Scraper implements scrapify.IScraper by rendering every page with a Browser and extracting the rendered content.
+type Scraper[T any] struct {
+	Browser     Browser                                                         // Browser rendering the pages.
+	PageWaitFor []string                                                        // Selectors to wait for on listing pages, before calling URLs.
+	ItemWaitFor []string                                                        // Selectors to wait for on item pages, before calling Data.
+	URLs        func(page *Page) (urls []string, nextPages []string, err error) // Extracts the item URLs and next pages of a listing page.
+	Data        func(page *Page) ([]T, error)                                   // Extracts the items of an item page.
+}
+
+// errNoBrowser is returned when the Scraper has no Browser.
+var errNoBrowser = errors.New("browser: no Browser configured")
+
+// GetUrls renders the listing page and extracts its item URLs and next pages with URLs.
+func (s *Scraper[T]) GetUrls(ctx context.Context, url string) ([]string, []string, error) {
+	page, err := s.render(ctx, url, s.PageWaitFor)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.URLs == nil {
+		return nil, nil, nil
+	}
+	return s.URLs(page)
+}
+
+// GetData renders the item page, extracts its items with Data and sends each of them to the channel.
+func (s *Scraper[T]) GetData(ctx context.Context, ch chan<- T, data *T, url string) error {
+	page, err := s.render(ctx, url, s.ItemWaitFor)
+	if err != nil {
+		return err
+	}
+	if s.Data == nil {
+		return nil
+	}
+
+	items, err := s.Data(page)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		*data = item
+		ch <- *data
+	}
+	return nil
+}
+
+// render loads the URL with the Browser.
+func (s *Scraper[T]) render(ctx context.Context, url string, waitFor []string) (*Page, error) {
+	if s.Browser == nil {
+		return nil, errNoBrowser
+	}
+	return s.Browser.Render(ctx, url, waitFor)
+}
+
+var _ scrapify.IScraper[struct{}] = (*Scraper[struct{}])(nil)
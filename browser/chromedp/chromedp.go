@@ -0,0 +1,75 @@
+// Package chromedp implements browser.Browser with a headless Chrome driven by github.com/chromedp/chromedp:
+//
+//	b, err := chromedp.New()
+//	if err != nil {
+//		return err
+//	}
+//	defer b.Close()
+//	scraper := &browser.Scraper[Product]{Browser: b, URLs: extractListing, Data: extractProducts}
+//
+// It is a module of its own, so the scrapify module does not depend on chromedp. Chrome or Chromium must be installed.
+package chromedp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chromedp/chromedp"
+	"github.com/ricardocastanho/scrapify/browser"
+)
+
+// Browser renders pages in a headless Chrome, each in a tab of its own, so it is safe for concurrent use.
+type Browser struct {
+	ctx    context.Context // Context of the browser, from which tabs are opened.
+	cancel func()          // Closes the browser and stops its process.
+}
+
+// New starts a headless Chrome with the default options of chromedp, followed by opts, such as
+// chromedp.UserAgent or chromedp.ExecPath. The browser runs until Close is called.
+func New(opts ...chromedp.ExecAllocatorOption) (*Browser, error) {
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(context.Background(), append(chromedp.DefaultExecAllocatorOptions[:], opts...)...)
+	ctx, cancelCtx := chromedp.NewContext(allocCtx)
+	cancel := func() {
+		cancelCtx()
+		cancelAlloc()
+	}
+
+	// Running no action starts the browser, so a missing Chrome fails here rather than on the first page.
+	if err := chromedp.Run(ctx); err != nil {
+		cancel()
+		return nil, fmt.Errorf("chromedp: starting the browser: %w", err)
+	}
+	return &Browser{ctx: ctx, cancel: cancel}, nil
+}
+
+// Render opens the URL in a new tab, waits until every CSS selector of waitFor is visible, and returns the final URL
+// and the outer HTML of the rendered document. The tab is closed when Render returns, or as soon as ctx is done.
+func (b *Browser) Render(ctx context.Context, url string, waitFor []string) (*browser.Page, error) {
+	tab, cancel := chromedp.NewContext(b.ctx)
+	defer cancel()
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	var page browser.Page
+	actions := []chromedp.Action{chromedp.Navigate(url)}
+	for _, selector := range waitFor {
+		actions = append(actions, chromedp.WaitVisible(selector, chromedp.ByQuery))
+	}
+	actions = append(actions, chromedp.Location(&page.URL), chromedp.OuterHTML("html", &page.HTML, chromedp.ByQuery))
+
+	if err := chromedp.Run(tab, actions...); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("chromedp: rendering %s: %w", url, err)
+	}
+	return &page, nil
+}
+
+// Close closes the browser. Pages being rendered fail.
+func (b *Browser) Close() error {
+	b.cancel()
+	return nil
+}
+
+var _ browser.Browser = (*Browser)(nil)
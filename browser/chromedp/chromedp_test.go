@@ -0,0 +1,71 @@
+package chromedp_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ricardocastanho/scrapify/browser"
+	"github.com/ricardocastanho/scrapify/browser/chromedp"
+)
+
+// newBrowser starts a headless Chrome, skipping the test when none is installed.
+func newBrowser(t *testing.T) *chromedp.Browser {
+	t.Helper()
+
+	b, err := chromedp.New()
+	if errors.Is(err, exec.ErrNotFound) {
+		t.Skip("Chrome is not installed")
+	}
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { b.Close() })
+	return b
+}
+
+// page is a document whose content is only rendered by JavaScript, after a delay.
+const page = `<html><body><div id="app"></div><script>
+setTimeout(function() { document.getElementById("app").innerHTML = '<p class="price">42</p>'; }, 100);
+</script></body></html>`
+
+func TestRenderWaitsForSelectors(t *testing.T) {
+	b := newBrowser(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, page)
+	}))
+	defer srv.Close()
+
+	got, err := b.Render(context.Background(), srv.URL+"/", []string{".price"})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if !strings.Contains(got.HTML, `<p class="price">42</p>`) {
+		t.Errorf("HTML %q, want the content rendered by the script", got.HTML)
+	}
+	if got.URL != srv.URL+"/" {
+		t.Errorf("URL %q, want %q", got.URL, srv.URL+"/")
+	}
+}
+
+func TestRenderStopsWithContext(t *testing.T) {
+	b := newBrowser(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html><body>never matches</body></html>")
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if _, err := b.Render(ctx, srv.URL, []string{".missing"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Render error %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+var _ browser.Browser = (*chromedp.Browser)(nil)
@@ -0,0 +1,56 @@
+package chromedp_test
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/ricardocastanho/scrapify"
+	"github.com/ricardocastanho/scrapify/browser"
+	"github.com/ricardocastanho/scrapify/browser/chromedp"
+)
+
+func ExampleNew() {
+	b, err := chromedp.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer b.Close()
+
+	// Listing pages link to products once their grid is rendered, and product pages show their title.
+	products := &browser.Scraper[string]{
+		Browser:     b,
+		PageWaitFor: []string{".product-grid"},
+		ItemWaitFor: []string{"h1"},
+		URLs: func(page *browser.Page) ([]string, []string, error) {
+			return extractLinks(page.HTML, `class="product" href="`), extractLinks(page.HTML, `rel="next" href="`), nil
+		},
+		Data: func(page *browser.Page) ([]string, error) {
+			_, title, _ := strings.Cut(page.HTML, "<h1>")
+			title, _, _ = strings.Cut(title, "</h1>")
+			return []string{title}, nil
+		},
+	}
+
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+		Scraper: products,
+		Url:     "https://shop.example/products",
+	}}, func(title string) { log.Println(title) }, 0)
+	if err := scraper.Run(context.Background()); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// extractLinks returns the URLs following each occurrence of prefix in the document, up to the closing quote.
+func extractLinks(html, prefix string) []string {
+	var urls []string
+	for {
+		_, rest, ok := strings.Cut(html, prefix)
+		if !ok {
+			return urls
+		}
+		var url string
+		url, html, _ = strings.Cut(rest, `"`)
+		urls = append(urls, url)
+	}
+}
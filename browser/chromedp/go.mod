@@ -0,0 +1,20 @@
+module github.com/ricardocastanho/scrapify/browser/chromedp
+
+go 1.23.0
+
+require (
+	github.com/chromedp/chromedp v0.13.7
+	github.com/ricardocastanho/scrapify v0.0.0
+)
+
+require (
+	github.com/chromedp/cdproto v0.0.0-20250403032234-65de8f5d025b // indirect
+	github.com/chromedp/sysutil v1.1.0 // indirect
+	github.com/go-json-experiment/json v0.0.0-20250211171154-1ae217ad3535 // indirect
+	github.com/gobwas/httphead v0.1.0 // indirect
+	github.com/gobwas/pool v0.2.1 // indirect
+	github.com/gobwas/ws v1.4.0 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+)
+
+replace github.com/ricardocastanho/scrapify => ../..
@@ -0,0 +1,41 @@
+package scrapify
+
+// WithMaxBytes stops the crawl once the response bodies recorded with RecordResponse, as the fetch client records
+// every response it reads, add up to n bytes or more, to bound its bandwidth or storage cost. StopReason then returns StopMaxBytes.
+// The crawl stops like on cancellation: in-flight calls are cancelled, and the items already scraped still reach the callback,
+// for up to the WithDrainTimeout if set. Responses fetched by in-flight calls may exceed the budget slightly.
+// The bytes downloaded are reported in Stats.Bytes whether or not they are bounded. The default of zero is unbounded.
+func WithMaxBytes(n int64) Option {
+	return func(o *options) {
+		o.maxBytes = n
+	}
+}
+
+// WithMaxItems stops the crawl once n items have been delivered to the callback, for crawls after the first n results,
+// such as the first 1000 products. StopReason then returns StopMaxItems. The crawl stops like on cancellation: in-flight
+// calls are cancelled and drained, and the items they still scrape are dropped rather than delivered, so the callback
+// receives exactly n items when the crawl has that many. Dropped and duplicate items do not count. The default of zero is unbounded.
+func WithMaxItems(n int64) Option {
+	return func(o *options) {
+		o.maxItems = n
+	}
+}
+
+// itemsExhausted reports whether the WithMaxItems budget is spent, so no more items are delivered.
+func (s *Scraper[T]) itemsExhausted() bool {
+	return s.maxItems > 0 && s.counters.items.Load() >= s.maxItems
+}
+
+// checkItems stops the crawl once its delivered items reach the WithMaxItems budget.
+func (s *Scraper[T]) checkItems() {
+	if s.itemsExhausted() {
+		s.stop(StopMaxItems)
+	}
+}
+
+// checkBytes stops the crawl once its downloaded bytes reach the WithMaxBytes budget.
+func (s *Scraper[T]) checkBytes() {
+	if s.maxBytes > 0 && s.counters.bytes.Load() >= s.maxBytes {
+		s.stop(StopMaxBytes)
+	}
+}
@@ -0,0 +1,44 @@
+package scrapify_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+func TestMaxItemsStopsAfterExactlyNItems(t *testing.T) {
+	var got collector[string]
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+		Scraper: funcScraper[string]{urls: listing(100), data: echo},
+		Url:     "https://example.com",
+	}}, got.add, 0, scrapify.WithConcurrency(8), scrapify.WithMaxItems(10))
+
+	if err := scraper.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if n := len(got.result()); n != 10 {
+		t.Errorf("delivered %d items, want 10", n)
+	}
+	if reason := scraper.StopReason(); reason != scrapify.StopMaxItems {
+		t.Errorf("stop reason %q, want %q", reason, scrapify.StopMaxItems)
+	}
+}
+
+func TestMaxItemsNotReached(t *testing.T) {
+	var got collector[string]
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+		Scraper: funcScraper[string]{urls: listing(5), data: echo},
+		Url:     "https://example.com",
+	}}, got.add, 0, scrapify.WithMaxItems(10))
+
+	if err := scraper.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if n := len(got.result()); n != 5 {
+		t.Errorf("delivered %d items, want 5", n)
+	}
+	if reason := scraper.StopReason(); reason != scrapify.StopCompleted {
+		t.Errorf("stop reason %q, want %q", reason, scrapify.StopCompleted)
+	}
+}
@@ -0,0 +1,229 @@
+package scrapify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// checkpointVersion is the version of the checkpoint file format written by this package.
+const checkpointVersion = 1
+
+// checkpoint is the on-disk representation of a crawl in progress.
+//
+// The file is a single JSON document:
+//
+//	{
+//	  "version": 1,
+//	  "saved_at": "2024-01-02T15:04:05Z",
+//	  "visited": ["https://example.com/item/1", ...],
+//	  "frontier": [{"url": "https://example.com/page/3", "strategy": 0, "kind": "page"}, ...]
+//	}
+//
+// Strategy is the index of the ScraperStrategy in the slice given to NewScraper, so a checkpoint must be resumed with the same strategy list.
+type checkpoint struct {
+	Version  int       `json:"version"`  // Format version, see checkpointVersion.
+	SavedAt  time.Time `json:"saved_at"` // Time at which the checkpoint was written.
+	Visited  []string  `json:"visited"`  // URLs whose work has been completed, prefixed by their strategy index with PerStrategyDedup.
+	Frontier []Work    `json:"frontier"` // Pending work, including work that was in flight when the checkpoint was written.
+}
+
+// WithCheckpoint periodically saves the crawl state to the file at path, every interval, so an interrupted crawl can be resumed.
+// The checkpoint contains the visited set and the frontier of pending pages and item URLs.
+// URLs that were in flight when the checkpoint was written are stored in the frontier rather than in the visited set,
+// so they are processed again after a restart (at-least-once semantics).
+// On Run, an existing checkpoint is loaded and the crawl resumes from its frontier instead of the seed URLs.
+// A crawl that completes without being cancelled removes its checkpoint; a cancelled crawl writes a final one.
+// If interval is zero or negative, the checkpoint is only written when the crawl is cancelled.
+// The pending pages of a resumed frontier are counted against the MaxPages of their strategy, but the pages visited
+// before the checkpoint are not.
+// Checkpointing requires a VisitedStore implementing ListableVisitedStore.
+func WithCheckpoint(path string, interval time.Duration) Option {
+	return func(o *options) {
+		o.checkpointPath = path
+		o.checkpointInterval = interval
+	}
+}
+
+// reserve adds the work to the frontier of pending work and reports whether it was not pending yet. It is the single atomic gate
+// of the work queued, so a URL discovered by two pages or strategies at once is queued once: it is claimed when queued,
+// and stays claimed until its work is done and it is marked as visited.
+func (s *Scraper[T]) reserve(w Work) bool {
+	s.frontierMu.Lock()
+	defer s.frontierMu.Unlock()
+
+	key := s.visitKey(w)
+	if _, ok := s.frontier[key]; ok {
+		return false
+	}
+	s.frontier[key] = w
+	return true
+}
+
+// untrack removes work from the frontier once it is done.
+func (s *Scraper[T]) untrack(w Work) {
+	s.frontierMu.Lock()
+	defer s.frontierMu.Unlock()
+
+	delete(s.frontier, s.visitKey(w))
+}
+
+// loadCheckpoint reads the checkpoint file, if checkpointing is enabled and the file exists.
+// It returns nil when there is nothing to resume.
+func (s *Scraper[T]) loadCheckpoint() (*checkpoint, error) {
+	if s.checkpointPath == "" {
+		return nil, nil
+	}
+
+	if _, ok := s.scrapedUrls.(ListableVisitedStore); !ok {
+		return nil, errors.New("scrapify: checkpointing requires a VisitedStore implementing ListableVisitedStore")
+	}
+
+	b, err := os.ReadFile(s.checkpointPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scrapify: reading checkpoint: %w", err)
+	}
+
+	var cp checkpoint
+	if err := json.Unmarshal(b, &cp); err != nil {
+		return nil, fmt.Errorf("scrapify: decoding checkpoint: %w", err)
+	}
+	if err := s.checkCheckpoint(&cp); err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+// checkCheckpoint checks that the checkpoint can be resumed with the configured strategies.
+func (s *Scraper[T]) checkCheckpoint(cp *checkpoint) error {
+	if cp.Version != checkpointVersion {
+		return fmt.Errorf("scrapify: unsupported checkpoint version %d", cp.Version)
+	}
+	for _, w := range cp.Frontier {
+		if w.Strategy < 0 || w.Strategy >= len(s.strategy) {
+			return fmt.Errorf("scrapify: checkpoint references strategy %d but only %d strategies are configured", w.Strategy, len(s.strategy))
+		}
+	}
+	return nil
+}
+
+// resume restores the visited set from a checkpoint and schedules its frontier.
+// Seed URLs that are neither visited nor pending in the checkpoint are then scheduled as usual by seed.
+func (s *Scraper[T]) resume(cp *checkpoint) {
+	for _, url := range cp.Visited {
+		s.scrapedUrls.Visit(url)
+	}
+
+	for _, w := range cp.Frontier {
+		s.hosts.add(hostKey(w.URL))
+
+		// Pages claim one of the MaxPages of their strategy, and are marked as visited when scheduled,
+		// so a page discovered twice is only scheduled once.
+		if w.Kind == PageWork {
+			if !s.takePage(w.Strategy) {
+				continue
+			}
+			if !s.scrapedUrls.Visit(s.visitKey(w)) {
+				s.returnPage(w.Strategy)
+				continue
+			}
+		}
+		s.push(w)
+	}
+}
+
+// saveCheckpoint writes the current crawl state to the checkpoint file.
+// The file is written to a temporary path first and then renamed, so a crash never leaves a truncated checkpoint.
+func (s *Scraper[T]) saveCheckpoint() error {
+	b, err := json.Marshal(s.capture())
+	if err != nil {
+		return fmt.Errorf("scrapify: encoding checkpoint: %w", err)
+	}
+
+	tmp := s.checkpointPath + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return fmt.Errorf("scrapify: writing checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, s.checkpointPath); err != nil {
+		return fmt.Errorf("scrapify: writing checkpoint: %w", err)
+	}
+	return nil
+}
+
+// capture returns the current crawl state. The VisitedStore must implement ListableVisitedStore.
+func (s *Scraper[T]) capture() checkpoint {
+	store := s.scrapedUrls.(ListableVisitedStore)
+
+	s.frontierMu.Lock()
+	cp := checkpoint{
+		Version:  checkpointVersion,
+		SavedAt:  s.clock.Now().UTC(),
+		Frontier: make([]Work, 0, len(s.frontier)),
+	}
+	for _, w := range s.frontier {
+		cp.Frontier = append(cp.Frontier, w)
+	}
+	pending := make(map[string]struct{}, len(s.frontier))
+	for url := range s.frontier {
+		pending[url] = struct{}{}
+	}
+	s.frontierMu.Unlock()
+
+	// In-flight URLs are already marked as visited but their work is not done, so they are kept in the frontier only.
+	for _, url := range store.URLs() {
+		if _, ok := pending[url]; !ok {
+			cp.Visited = append(cp.Visited, url)
+		}
+	}
+	return cp
+}
+
+// startCheckpointing saves a checkpoint every checkpoint interval until the returned function is called.
+func (s *Scraper[T]) startCheckpointing() func() {
+	if s.checkpointPath == "" || s.checkpointInterval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-s.clock.After(s.checkpointInterval):
+				// Periodic saves are best effort; the final save reports its error from Run.
+				_ = s.saveCheckpoint()
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// finishCheckpoint writes a final checkpoint for a cancelled crawl, or removes the checkpoint of a completed one.
+func (s *Scraper[T]) finishCheckpoint(ctx context.Context) error {
+	if s.checkpointPath == "" {
+		return nil
+	}
+
+	if ctx.Err() != nil {
+		return s.saveCheckpoint()
+	}
+
+	if err := os.Remove(s.checkpointPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("scrapify: removing checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package scrapify_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+func TestResumedPagesCountAgainstMaxPages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	cp := `{"version": 1, "saved_at": "2024-01-02T15:04:05Z", "visited": ["https://example.com/list"], "frontier": [
+		{"url": "https://example.com/list?page=2", "strategy": 0, "kind": "page"},
+		{"url": "https://example.com/list?page=3", "strategy": 0, "kind": "page"},
+		{"url": "https://example.com/list?page=4", "strategy": 0, "kind": "page"}]}`
+	if err := os.WriteFile(path, []byte(cp), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var pages atomic.Int32
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+		Scraper: funcScraper[string]{urls: func(ctx context.Context, url string) ([]string, []string, error) {
+			pages.Add(1)
+			return nil, nil, nil
+		}},
+		Url:      "https://example.com/list",
+		MaxPages: 2,
+	}}, nil, 0, scrapify.WithCheckpoint(path, time.Hour))
+
+	if err := scraper.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got := pages.Load(); got != 2 {
+		t.Errorf("GetUrls called on %d resumed pages, want MaxPages = 2", got)
+	}
+}
@@ -0,0 +1,38 @@
+package scrapify
+
+import "time"
+
+// Clock provides the current time and timers to the Scraper.
+// Every delay, rate limit and periodic task goes through it, so tests can substitute a fake clock
+// (such as scrapifytest.FakeClock) and control time deterministically.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After returns a channel receiving the current time once the duration has elapsed.
+	After(d time.Duration) <-chan time.Time
+
+	// Sleep blocks for the duration.
+	Sleep(d time.Duration)
+}
+
+// systemClock is the default Clock, backed by the time package.
+type systemClock struct{}
+
+// Now returns the current time.
+func (systemClock) Now() time.Time { return time.Now() }
+
+// After returns a channel receiving the current time once the duration has elapsed.
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Sleep blocks for the duration.
+func (systemClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// WithClock sets the Clock used for all the timing of the Scraper. The default is the system clock.
+func WithClock(clock Clock) Option {
+	return func(o *options) {
+		if clock != nil {
+			o.clock = clock
+		}
+	}
+}
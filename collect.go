@@ -0,0 +1,37 @@
+package scrapify
+
+import (
+	"context"
+	"slices"
+)
+
+// RunAndCollect runs the crawl like Run and returns every item it delivered, in the order they were delivered,
+// along with the error Run returned. The items delivered before a failure or a cancellation are returned too.
+// The callback given to NewScraper, if any, is still invoked for every item. Items are kept in memory until Run returns.
+func (s *Scraper[T]) RunAndCollect(ctx context.Context) ([]T, error) {
+	sink := NewCollectingSink[T]()
+
+	itemHook := s.itemHook
+	s.itemHook = sink.Callback
+	defer func() { s.itemHook = itemHook }()
+
+	err := s.Run(ctx)
+	return sink.Result(), err
+}
+
+// RunAndCollectSorted runs the crawl like RunAndCollect and returns its items sorted by less, which reports whether a sorts before b,
+// since concurrent scrapers deliver items in no particular order. Items comparing equal keep their delivery order.
+func (s *Scraper[T]) RunAndCollectSorted(ctx context.Context, less func(a, b T) bool) ([]T, error) {
+	items, err := s.RunAndCollect(ctx)
+	slices.SortStableFunc(items, func(a, b T) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		default:
+			return 0
+		}
+	})
+	return items, err
+}
@@ -0,0 +1,52 @@
+package scrapify
+
+// WithShouldContinue registers a predicate consulted before following the next pages of every page,
+// with the stats accumulated so far and the last item delivered for the page's strategy (the zero value if none was).
+// When it returns false, the next pages of that page are dropped while its item URLs are still scraped,
+// which stops pagination once enough items were collected or, on time-ordered feeds, once items get too old.
+// Items are delivered while other pages are processed, so the predicate sees the results accumulated at the time it is called.
+// T must be the type of data of the Scraper, otherwise Run and Probe fail without crawling.
+func WithShouldContinue[T any](fn func(accumulated Stats, lastItem T) bool) Option {
+	return func(o *options) {
+		o.shouldContinue = fn
+	}
+}
+
+// continuePredicate returns the predicate registered with WithShouldContinue, checking it matches the type of data of the Scraper.
+// A mismatch is added to errs and disables the option.
+func continuePredicate[T any](o options, errs *[]error) func(Stats, T) bool {
+	if o.shouldContinue == nil {
+		return nil
+	}
+
+	fn, ok := o.shouldContinue.(func(Stats, T) bool)
+	if !ok {
+		*errs = append(*errs, mismatch[T]("WithShouldContinue predicate", o.shouldContinue))
+	}
+	return fn
+}
+
+// remember records the last item delivered for a strategy, when a ShouldContinue predicate needs it.
+func (s *Scraper[T]) remember(strategy int, item T) {
+	if s.shouldContinue == nil {
+		return
+	}
+
+	s.lastMu.Lock()
+	defer s.lastMu.Unlock()
+
+	s.lastItems[strategy] = item
+}
+
+// following reports whether the next pages of a page of the strategy should be followed.
+func (s *Scraper[T]) following(strategy int) bool {
+	if s.shouldContinue == nil {
+		return true
+	}
+
+	s.lastMu.Lock()
+	last := s.lastItems[strategy]
+	s.lastMu.Unlock()
+
+	return s.shouldContinue(s.Stats(), last)
+}
@@ -0,0 +1,70 @@
+package scrapify
+
+// WithCrashFlush registers a function invoked with the items scraped but not delivered to the callback yet when a panic
+// propagates out of a scraper, the callback, a handler or a hook, right before it crashes the process, so a long crawl can persist
+// what would otherwise be lost. The items are passed as sent by the scrapers, before WithTransform: those buffered by
+// WithResultBuffer or WithCallbackRateLimit and, when the panic happens while delivering items, those held back by
+// WithOrderedPagination. fn is also the place to flush the buffers of the callback, such as the last batch of a sink.
+// It is invoked once, by the first panicking goroutine, and the panic then propagates unchanged.
+// Flushing is best-effort: it does not cover panics in goroutines started by the scrapers themselves,
+// fatal runtime errors, os.Exit or the process being killed, and the crawl keeps running in the other goroutines meanwhile.
+// T must be the type of data of the Scraper, otherwise Run and Probe fail without crawling.
+func WithCrashFlush[T any](fn func(undelivered []T)) Option {
+	return func(o *options) {
+		o.crashFlush = fn
+	}
+}
+
+// crashHandler returns the function registered with WithCrashFlush, checking it matches the type of data of the Scraper.
+// A mismatch is added to errs and disables the option.
+func crashHandler[T any](o options, errs *[]error) func([]T) {
+	if o.crashFlush == nil {
+		return nil
+	}
+
+	fn, ok := o.crashFlush.(func([]T))
+	if !ok {
+		*errs = append(*errs, mismatch[T]("WithCrashFlush function", o.crashFlush))
+	}
+	return fn
+}
+
+// recoverCrash flushes the undelivered items with WithCrashFlush when the goroutine panics, then propagates the panic.
+// It must be deferred directly, with the reorder buffer of the consumer when deferred by it, nil otherwise.
+func (s *Scraper[T]) recoverCrash(buf *reorderBuffer[T]) {
+	if s.crashFlush == nil {
+		return
+	}
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	s.crashOnce.Do(func() {
+		s.crashFlush(s.undelivered(buf))
+	})
+	panic(r)
+}
+
+// undelivered returns the items held in the reorder buffer, if any, and those waiting in the data channel.
+func (s *Scraper[T]) undelivered(buf *reorderBuffer[T]) []T {
+	var items []T
+	if buf != nil {
+		for _, key := range buf.keys() {
+			for _, d := range buf.held[key] {
+				items = append(items, d.item)
+			}
+		}
+	}
+	for {
+		select {
+		case d, ok := <-s.ch:
+			if !ok {
+				return items
+			}
+			items = append(items, d.item)
+		default:
+			return items
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package scrapify
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// CrawlDelayLookup returns the minimum delay between two requests to an origin, made of the scheme and host of a URL like "https://example.com".
+type CrawlDelayLookup func(ctx context.Context, origin string) (time.Duration, error)
+
+// WithCrawlDelay spaces out the requests to each host by the delay returned by lookup for that host,
+// such as the Crawl-delay directive of its robots.txt, which fetch.RobotsCrawlDelay reads.
+// lookup is called once per origin, the first time a URL of that origin is requested.
+// Hosts for which it returns zero or an error are not delayed, and only the requestDelay given to NewScraper applies to them;
+// for the others, both apply. Both GetUrls and GetData calls count as requests.
+func WithCrawlDelay(lookup CrawlDelayLookup) Option {
+	return func(o *options) {
+		o.crawlDelay = lookup
+	}
+}
+
+// crawlDelays enforces the crawl delay of each origin, resolving it on first use.
+type crawlDelays struct {
+	lookup  CrawlDelayLookup        // Resolves the delay of an origin.
+	limiter *rateLimiter            // Reserves the slots of each origin, with its own interval.
+	mu      sync.Mutex              // Guards origins.
+	origins map[string]*originDelay // Delays keyed by origin.
+}
+
+// originDelay is the crawl delay of an origin, available once ready is closed.
+type originDelay struct {
+	ready chan struct{} // Closed once delay is resolved.
+	delay time.Duration // Minimum delay between two requests to the origin.
+}
+
+// newCrawlDelays creates the crawl delays, or returns nil when no lookup is configured.
+func newCrawlDelays(o options) *crawlDelays {
+	if o.crawlDelay == nil {
+		return nil
+	}
+
+	return &crawlDelays{
+		lookup:  o.crawlDelay,
+		limiter: &rateLimiter{clock: o.clock, next: make(map[string]time.Time)},
+		origins: make(map[string]*originDelay),
+	}
+}
+
+// wait blocks until a request to the URL's origin respects its crawl delay, or until the context is done.
+// The delay of a new origin is resolved with lookupCtx, so it does not depend on the request that triggered it.
+func (c *crawlDelays) wait(ctx, lookupCtx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil
+	}
+	origin := u.Scheme + "://" + u.Host
+
+	c.mu.Lock()
+	d, ok := c.origins[origin]
+	if !ok {
+		d = &originDelay{ready: make(chan struct{})}
+		c.origins[origin] = d
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		if delay, err := c.lookup(lookupCtx, origin); err == nil {
+			d.delay = delay
+		}
+		close(d.ready)
+	}
+
+	select {
+	case <-d.ready:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	if d.delay <= 0 {
+		return nil
+	}
+	return c.limiter.waitInterval(ctx, origin, d.delay)
+}
@@ -0,0 +1,45 @@
+package scrapify_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ricardocastanho/scrapify"
+	"github.com/ricardocastanho/scrapify/scrapifytest"
+)
+
+func TestCrawlDelayLongerThanRequestTimeout(t *testing.T) {
+	clock := scrapifytest.NewFakeClock(time.Now())
+	var got collector[string]
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+		Scraper: funcScraper[string]{urls: listing(2), data: func(ctx context.Context, ch chan<- string, url string) error {
+			// A timeout started before the crawl delay would already have expired.
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			return echo(ctx, ch, url)
+		}},
+		Url: "https://example.com/list",
+	}}, got.add, 0,
+		scrapify.WithClock(clock),
+		scrapify.WithCrawlDelay(func(ctx context.Context, origin string) (time.Duration, error) { return 10 * time.Second, nil }),
+		scrapify.WithRequestTimeout(50*time.Millisecond))
+
+	done := scraper.Start(context.Background())
+	for {
+		select {
+		case <-done:
+			if err := scraper.Err(); err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			if n := len(got.result()); n != 2 {
+				t.Fatalf("%d items delivered, want 2", n)
+			}
+			return
+		case <-time.After(100 * time.Millisecond):
+			// Let the request timeout elapse in real time while the item URLs wait for their crawl delay, then release one.
+			clock.Advance(10 * time.Second)
+		}
+	}
+}
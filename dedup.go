@@ -0,0 +1,45 @@
+package scrapify
+
+import "strconv"
+
+// DedupScope tells which strategies share the set of visited URLs.
+type DedupScope int
+
+const (
+	// GlobalDedup visits every URL once across all strategies. It is the default.
+	GlobalDedup DedupScope = iota
+
+	// PerStrategyDedup visits every URL once per strategy, so several strategies can scrape the same URL,
+	// for instance when it yields different data depending on the strategy.
+	PerStrategyDedup
+)
+
+// WithDedupScope sets whether URLs are deduplicated across all strategies or within each strategy.
+// With PerStrategyDedup, the visited set is keyed by strategy index and URL: a URL reached by n strategies is stored n times,
+// so the memory used by the visited set, and by checkpoints, grows with the overlap between strategies.
+// A checkpoint must be resumed with the scope it was written with.
+func WithDedupScope(scope DedupScope) Option {
+	return func(o *options) {
+		o.dedupScope = scope
+	}
+}
+
+// visitKey returns the key of the work in the visited set and in the frontier.
+func (s *Scraper[T]) visitKey(w Work) string {
+	if s.dedupScope == PerStrategyDedup {
+		return strconv.Itoa(w.Strategy) + " " + w.URL
+	}
+	return w.URL
+}
+
+// visitCanonical marks the canonical URL of a response of the work as visited, when it differs from the URL of the work,
+// so the canonical URL is not fetched again. It reports whether the canonical URL was already visited,
+// in which case the response duplicates content already crawled.
+func (s *Scraper[T]) visitCanonical(w Work, info ResponseInfo) bool {
+	if info.Canonical == "" {
+		return false
+	}
+
+	canonical := s.canonical(info.Canonical)
+	return canonical != w.URL && !s.scrapedUrls.Visit(s.visitKey(Work{URL: canonical, Strategy: w.Strategy, Kind: w.Kind}))
+}
@@ -0,0 +1,46 @@
+package scrapify
+
+import (
+	"context"
+	"time"
+)
+
+// WithDrainTimeout bounds the time Run waits for in-flight work once the crawl is stopped, by cancellation or by the
+// Scraper itself, so it returns within d of the stop even if requests hang. Past d, the remaining GetUrls and GetData
+// calls are abandoned: their goroutines are left running until they return, their items are dropped, and Run closes
+// the channels and returns. The callback running when d elapses, if any, is still waited for.
+// Abandoned calls may still invoke the error hooks after Run returns. The default of zero waits for all in-flight work.
+func WithDrainTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.drainTimeout = d
+	}
+}
+
+// drain waits for the dispatched work to complete, or, with WithDrainTimeout, for up to the drain timeout after the crawl
+// is stopped. It reports whether in-flight work was abandoned, in which case the abandoned channel is closed.
+func (s *Scraper[T]) drain(ctx context.Context) bool {
+	if s.drainTimeout <= 0 {
+		s.wg.Wait()
+		return false
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return false
+	case <-ctx.Done():
+	}
+
+	select {
+	case <-drained:
+		return false
+	case <-s.clock.After(s.drainTimeout):
+		close(s.abandoned)
+		return true
+	}
+}
@@ -0,0 +1,73 @@
+package scrapify
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrDraining is returned when work is enqueued after the crawl has stopped accepting new work,
+// because it was cancelled or all pending work has completed.
+var ErrDraining = errors.New("scrapify: crawl is draining")
+
+// CrawlHandle lets a callback or a scraper add URLs to the running crawl.
+// URLs go through the same deduplication, pacing and scheduling as the ones returned by GetUrls,
+// and are attributed to the strategy the handle belongs to.
+type CrawlHandle struct {
+	schedule func(Work) error // Admits work to the running crawl.
+	strategy int              // Index of the strategy new work is attributed to.
+	depth    int              // Depth of the work the handle was created for; new work is one hop deeper.
+}
+
+// Enqueue adds an item URL, to be scraped with GetData.
+// It returns ErrDraining if the crawl no longer accepts new work.
+func (h *CrawlHandle) Enqueue(url string) error {
+	return h.schedule(Work{URL: url, Strategy: h.strategy, Kind: ItemWork, Depth: h.depth + 1})
+}
+
+// EnqueuePage adds a page, whose item URLs and next pages are discovered with GetUrls.
+// It returns ErrDraining if the crawl no longer accepts new work.
+func (h *CrawlHandle) EnqueuePage(url string) error {
+	return h.schedule(Work{URL: url, Strategy: h.strategy, Kind: PageWork, Depth: h.depth + 1})
+}
+
+// handleKey is the context key under which the CrawlHandle of the current work is stored.
+type handleKey struct{}
+
+// CrawlHandleFromContext returns the CrawlHandle stored in the context passed to GetUrls and GetData,
+// letting a scraper enqueue URLs it finds while parsing a page.
+func CrawlHandleFromContext(ctx context.Context) (*CrawlHandle, bool) {
+	h, ok := ctx.Value(handleKey{}).(*CrawlHandle)
+	return h, ok
+}
+
+// OnItem registers a handler invoked for every scraped item, after the callback given to NewScraper,
+// with a CrawlHandle to enqueue URLs discovered while processing the item.
+// T must be the type of data of the Scraper, otherwise Run and Probe fail without crawling.
+func OnItem[T any](fn func(item T, h *CrawlHandle)) Option {
+	return func(o *options) {
+		o.onItem = fn
+	}
+}
+
+// itemHandler returns the handler registered with OnItem, checking it matches the type of data of the Scraper.
+// A mismatch is added to errs and disables the handler.
+func itemHandler[T any](o options, errs *[]error) func(T, *CrawlHandle) {
+	if o.onItem == nil {
+		return nil
+	}
+
+	fn, ok := o.onItem.(func(T, *CrawlHandle))
+	if !ok {
+		*errs = append(*errs, mismatch[T]("OnItem handler", o.onItem))
+	}
+	return fn
+}
+
+// handle returns a CrawlHandle attributing new work to the strategy of the given work, one hop deeper.
+func (s *Scraper[T]) handle(ctx context.Context, from Work) *CrawlHandle {
+	return &CrawlHandle{
+		schedule: func(w Work) error { return s.schedule(ctx, w) },
+		strategy: from.Strategy,
+		depth:    from.Depth,
+	}
+}
@@ -0,0 +1,191 @@
+package scrapify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// WithFirstErrorStops makes the crawl fail fast: the first error returned by GetUrls or GetData cancels the crawl,
+// and Run returns that error once in-flight work has drained.
+// By default every error is collected and Run returns all of them joined together.
+func WithFirstErrorStops() Option {
+	return func(o *options) {
+		o.firstErrorStops = true
+	}
+}
+
+// OnDiscoveryError registers a hook invoked with every error of a GetUrls call, after OnError.
+// Discovery failures lose the whole subtree of a page, so they often deserve more attention than item failures.
+func OnDiscoveryError(fn func(err error)) Option {
+	return func(o *options) {
+		o.onDiscoveryError = fn
+	}
+}
+
+// OnDataError registers a hook invoked with every error of a GetData call, after OnError.
+func OnDataError(fn func(err error)) Option {
+	return func(o *options) {
+		o.onDataError = fn
+	}
+}
+
+// WithMaxErrors aborts the crawl once more than count errors have been recorded, a sign that something is
+// systematically wrong, such as the site being down or the crawl being blocked. StopReason then returns StopMaxErrors.
+// The default of zero never aborts.
+func WithMaxErrors(count int) Option {
+	return func(o *options) {
+		o.maxErrors = count
+	}
+}
+
+// WithMaxErrorRate aborts the crawl once the errors exceed fraction of the GetUrls and GetData calls made so far,
+// retries excluded, after at least minSamples calls. StopReason then returns StopErrorRate.
+// A fraction of zero or less never aborts.
+func WithMaxErrorRate(fraction float64, minSamples int) Option {
+	return func(o *options) {
+		o.maxErrorRate = fraction
+		o.errorRateSamples = minSamples
+	}
+}
+
+// OnError registers a hook invoked with every error recorded during the crawl, as soon as it happens.
+// The errors are still returned by Run.
+func OnError(fn func(err error)) Option {
+	return func(o *options) {
+		o.onError = fn
+	}
+}
+
+// Sentinel errors for the common failure categories, to be matched with errors.Is.
+// Scrapers return them, possibly wrapped, to tell why a URL failed. ErrTimeout also matches the errors of calls exceeding their WithRequestTimeout.
+// ErrMaxDepthExceeded, ErrFiltered, ErrRobotsDisallowed and ErrMaxPagesReached are permanent: calls failing with them are never retried.
+var (
+	ErrMaxDepthExceeded = errors.New("scrapify: max depth exceeded")       // The URL is deeper than the scraper is willing to go.
+	ErrFiltered         = errors.New("scrapify: filtered")                 // The URL was deliberately excluded.
+	ErrRobotsDisallowed = errors.New("scrapify: disallowed by robots.txt") // The site's robots.txt forbids fetching the URL.
+	ErrRateLimited      = errors.New("scrapify: rate limited")             // The site rejected the request for exceeding its rate limit, such as with a 429 status.
+	ErrTimeout          = errors.New("scrapify: timeout")                  // The request took too long.
+	ErrMaxPagesReached  = errors.New("scrapify: max pages reached")        // The scraper reached the number of pages it is willing to fetch.
+)
+
+// permanent reports whether the error belongs to a category that retrying cannot fix.
+func permanent(err error) bool {
+	return errors.Is(err, ErrMaxDepthExceeded) || errors.Is(err, ErrFiltered) ||
+		errors.Is(err, ErrRobotsDisallowed) || errors.Is(err, ErrMaxPagesReached)
+}
+
+// ScrapeError is the error recorded for a failed GetUrls or GetData call, as returned by Run and passed to RunStream.
+// Use errors.As to get the URL and depth of the failure, and errors.Is to match its category.
+type ScrapeError struct {
+	Op         string // Failed operation, "get urls" or "get data", or "callback" for a sink failing to write an item.
+	URL        string // URL being processed.
+	Strategy   int    // Index of the strategy of the URL in the list given to NewScraper.
+	StrategyID string // ID of the strategy of the URL.
+	Depth      int    // Depth of the URL, see Work.Depth.
+	Err        error  // Error returned by the scraper.
+}
+
+// Error describes the failed operation, its URL and the underlying error.
+func (e *ScrapeError) Error() string {
+	return fmt.Sprintf("scrapify: %s %s: %v", e.Op, e.URL, e.Err)
+}
+
+// Unwrap returns the error returned by the scraper.
+func (e *ScrapeError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the failure was a call exceeding its timeout, when target is ErrTimeout.
+// Other categories are matched through the underlying error.
+func (e *ScrapeError) Is(target error) bool {
+	return target == ErrTimeout && errors.Is(e.Err, context.DeadlineExceeded)
+}
+
+// SoftFailure is returned by GetUrls or GetData for a page that was fetched successfully but is not usable,
+// such as a "page not found" or "you have been blocked" page served with a 200 status.
+// The framework treats it like any other error: the call is retried when WithRetry is set, and the error is reported once retries are exhausted.
+// Soft failures are also counted separately in Stats.SoftFailures, so blocked crawls do not look successful.
+type SoftFailure struct {
+	Reason string // Why the page is not usable, such as "blocked" or "not found".
+	Err    error  // Optional underlying error.
+}
+
+// Error returns the reason of the soft failure.
+func (e *SoftFailure) Error() string {
+	if e.Err != nil {
+		return "soft failure: " + e.Reason + ": " + e.Err.Error()
+	}
+	return "soft failure: " + e.Reason
+}
+
+// Unwrap returns the underlying error.
+func (e *SoftFailure) Unwrap() error {
+	return e.Err
+}
+
+// reportError records an error returned by the scraper while processing the given work, as a ScrapeError.
+// Errors caused by the crawl being cancelled are not recorded.
+func (s *Scraper[T]) reportError(ctx context.Context, op string, w Work, err error) {
+	if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		return
+	}
+
+	scrapeErr := &ScrapeError{Op: op, URL: w.URL, Strategy: w.Strategy, StrategyID: s.strategy[w.Strategy].ID, Depth: w.Depth, Err: err}
+	err = scrapeErr
+
+	s.errMu.Lock()
+	if s.firstErrorStops && len(s.errs) > 0 {
+		s.errMu.Unlock()
+		return
+	}
+	s.errs = append(s.errs, err)
+	errorHook := s.errorHook
+	s.errMu.Unlock()
+	s.counters.errors.Add(1)
+	s.strategyStats[w.Strategy].errors.Add(1)
+	if soft := (*SoftFailure)(nil); errors.As(err, &soft) {
+		s.counters.softFailures.Add(1)
+		s.strategyStats[w.Strategy].softFailures.Add(1)
+	}
+
+	if errorHook != nil {
+		errorHook(err)
+	}
+	if s.onError != nil {
+		s.onError(err)
+	}
+	if w.Kind == PageWork && s.onDiscoveryError != nil {
+		s.onDiscoveryError(err)
+	}
+	if w.Kind == ItemWork && s.onDataError != nil {
+		s.onDataError(err)
+	}
+	s.publishError(scrapeErr)
+	if s.firstErrorStops {
+		s.stop(StopFirstError)
+	}
+	s.checkErrors()
+}
+
+// checkErrors aborts the crawl when the errors exceed the thresholds of WithMaxErrors or WithMaxErrorRate.
+func (s *Scraper[T]) checkErrors() {
+	errs := s.counters.errors.Load()
+	if s.maxErrors > 0 && errs > int64(s.maxErrors) {
+		s.stop(StopMaxErrors)
+		return
+	}
+
+	calls := s.counters.pages.Load() + s.counters.urls.Load()
+	if s.maxErrorRate > 0 && calls >= int64(s.errorRateSamples) && float64(errs) > s.maxErrorRate*float64(calls) {
+		s.stop(StopErrorRate)
+	}
+}
+
+// err returns the errors recorded during the crawl, joined together.
+func (s *Scraper[T]) err() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+
+	return errors.Join(s.errs...)
+}
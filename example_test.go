@@ -0,0 +1,83 @@
+package scrapify_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+// Product and Review are the items of two scrapers of a heterogeneous crawl.
+type Product struct{ Name string }
+type Review struct{ Stars int }
+
+// productScraper scrapes a product from each of the two item URLs of its listing page.
+type productScraper struct{}
+
+func (productScraper) GetUrls(ctx context.Context, url string) ([]string, []string, error) {
+	return []string{url + "/lamp", url + "/desk"}, nil, nil
+}
+
+func (productScraper) GetData(ctx context.Context, ch chan<- Product, data *Product, url string) error {
+	*data = Product{Name: url[len("https://shop.example/products/"):]}
+	ch <- *data
+	return nil
+}
+
+// reviewScraper scrapes a review from the single item URL of its listing page.
+type reviewScraper struct{}
+
+func (reviewScraper) GetUrls(ctx context.Context, url string) ([]string, []string, error) {
+	return []string{url + "/1"}, nil, nil
+}
+
+func (reviewScraper) GetData(ctx context.Context, ch chan<- Review, data *Review, url string) error {
+	*data = Review{Stars: 5}
+	ch <- *data
+	return nil
+}
+
+func ExampleAsAny() {
+	var mu sync.Mutex
+	var items []string
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[any]{
+		{Scraper: scrapify.AsAny[Product](productScraper{}), Url: "https://shop.example/products"},
+		{Scraper: scrapify.AsAny[Review](reviewScraper{}), Url: "https://shop.example/reviews"},
+	}, func(item any) {
+		mu.Lock()
+		defer mu.Unlock()
+		items = append(items, fmt.Sprintf("%T %+v", item, item))
+	}, 0)
+
+	if err := scraper.Run(context.Background()); err != nil {
+		fmt.Println(err)
+	}
+
+	// Items arrive concurrently, so sort them for a stable output.
+	slices.Sort(items)
+	for _, item := range items {
+		fmt.Println(item)
+	}
+	// Output:
+	// scrapify_test.Product {Name:desk}
+	// scrapify_test.Product {Name:lamp}
+	// scrapify_test.Review {Stars:5}
+}
+
+func ExampleTypedCallback() {
+	router := scrapify.NewTypedCallback()
+	scrapify.Handle(router, func(p Product) { fmt.Println("product:", p.Name) })
+	scrapify.Handle(router, func(r Review) { fmt.Println("review:", r.Stars, "stars") })
+	router.Fallback(func(item any) { fmt.Printf("unexpected %T\n", item) })
+
+	// Callback is what NewScraper receives for a Scraper[any]; it is called directly here to show the routing.
+	router.Callback(Product{Name: "lamp"})
+	router.Callback(Review{Stars: 4})
+	router.Callback("a string")
+	// Output:
+	// product: lamp
+	// review: 4 stars
+	// unexpected string
+}
@@ -0,0 +1,104 @@
+package fetch
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// WithResponseArchive writes every fetched response to dir, to inspect offline the raw content that produced a bad item.
+// Each response is stored as <key>.body, holding the decoded body, and <key>.json, holding its metadata,
+// where key is ArchiveKey of the request URL. The directory is created if needed.
+// Failing to write the archive fails the request, so a broken archive never goes unnoticed.
+func WithResponseArchive(dir string) Option {
+	return func(c *Client) {
+		c.archive = dir
+	}
+}
+
+// OnPage registers a hook invoked with the final URL and the decoded body of every fetched response, before the scraper
+// extracts anything from it, to store raw documents or run a secondary analysis alongside item extraction.
+// It runs synchronously in the request, so a slow hook slows down the crawl. The body is only valid during the call:
+// copy it to keep it. No hook is invoked by default, so bodies are not retained beyond the response.
+func OnPage(fn func(url string, body []byte)) Option {
+	return func(c *Client) {
+		c.onPage = fn
+	}
+}
+
+// ArchiveKey returns the name, without extension, under which WithResponseArchive stores the response of the URL:
+// the hex-encoded SHA-256 hash of the URL.
+func ArchiveKey(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:])
+}
+
+// archiveEntry is the metadata of an archived response.
+type archiveEntry struct {
+	URL        string      `json:"url"`         // URL of the request.
+	FinalURL   string      `json:"final_url"`   // URL of the response, after redirects.
+	Method     string      `json:"method"`      // Method of the request.
+	StatusCode int         `json:"status_code"` // HTTP status code.
+	Header     http.Header `json:"header"`      // Response headers.
+	FetchedAt  time.Time   `json:"fetched_at"`  // When the response was received.
+}
+
+// store writes the response of the request to the archive directory.
+func (c *Client) store(req *http.Request, resp *Response) error {
+	if err := os.MkdirAll(c.archive, 0o755); err != nil {
+		return fmt.Errorf("fetch: archive response: %w", err)
+	}
+
+	meta, err := json.MarshalIndent(archiveEntry{
+		URL:        req.URL.String(),
+		FinalURL:   resp.URL,
+		Method:     req.Method,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		FetchedAt:  time.Now().UTC(),
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("fetch: archive response: %w", err)
+	}
+
+	base := filepath.Join(c.archive, ArchiveKey(req.URL.String()))
+	if err := os.WriteFile(base+".body", resp.Body, 0o644); err != nil {
+		return fmt.Errorf("fetch: archive response: %w", err)
+	}
+	if err := os.WriteFile(base+".json", meta, 0o644); err != nil {
+		return fmt.Errorf("fetch: archive response: %w", err)
+	}
+	return nil
+}
+
+// LoadArchived reads the response of the URL from an archive written by WithResponseArchive.
+// The error wraps fs.ErrNotExist when the URL has not been archived.
+func LoadArchived(dir, url string) (*Response, error) {
+	base := filepath.Join(dir, ArchiveKey(url))
+
+	meta, err := os.ReadFile(base + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("fetch: load archived response of %s: %w", url, err)
+	}
+	var entry archiveEntry
+	if err := json.Unmarshal(meta, &entry); err != nil {
+		return nil, fmt.Errorf("fetch: load archived response of %s: %w", url, err)
+	}
+
+	body, err := os.ReadFile(base + ".body")
+	if err != nil {
+		return nil, fmt.Errorf("fetch: load archived response of %s: %w", url, err)
+	}
+
+	return &Response{
+		URL:        entry.FinalURL,
+		StatusCode: entry.StatusCode,
+		Header:     entry.Header,
+		Body:       body,
+	}, nil
+}
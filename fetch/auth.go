@@ -0,0 +1,154 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRedirects is the number of redirects followed by a request, as by the standard library client.
+const maxRedirects = 10
+
+// AuthConfig holds the credentials sent to a host, see WithHostAuth.
+// Refresh takes precedence over Token, which takes precedence over basic auth. Formatting an AuthConfig redacts its secrets,
+// so it can be logged safely.
+type AuthConfig struct {
+	Username string                                                                // User name of basic auth (empty disables basic auth).
+	Password string                                                                // Password of basic auth.
+	Token    string                                                                // Static bearer token.
+	Refresh  func(ctx context.Context) (token string, expiry time.Time, err error) // Fetches a bearer token valid until expiry (zero never expires).
+}
+
+// String describes the kind of credentials, without their secrets.
+func (a AuthConfig) String() string {
+	switch {
+	case a.Refresh != nil:
+		return "bearer token (refreshed)"
+	case a.Token != "":
+		return "bearer token"
+	case a.Username != "":
+		return "basic auth for " + a.Username
+	default:
+		return "no credentials"
+	}
+}
+
+// GoString describes the kind of credentials for the %#v verb, without their secrets.
+func (a AuthConfig) GoString() string {
+	return "fetch.AuthConfig{" + a.String() + "}"
+}
+
+// hostAuth holds the credentials of a host, with the bearer token last fetched by their Refresh function.
+type hostAuth struct {
+	config AuthConfig // Credentials of the host.
+	mu     sync.Mutex // Guards token and expiry, and serializes the refreshes.
+	token  string     // Last token fetched by Refresh (empty until fetched or once invalidated).
+	expiry time.Time  // When token expires (zero never expires).
+}
+
+// WithHostAuth sends the credentials of auth with every request to host, a host name such as api.example.com,
+// or a host and port to only match that port, so scrapers of authenticated APIs do not handle secrets themselves.
+// Each request of a redirect chain gets the credentials of its own host, and none when its host has none configured,
+// so they never leak to another host. A request setting its own Authorization header keeps it.
+// With Refresh, the token is fetched on the first request and again once it expires or the host answers 401 Unauthorized;
+// concurrent requests share a single refresh. A failing refresh fails the request.
+func WithHostAuth(host string, auth AuthConfig) Option {
+	return func(c *Client) {
+		if c.hostAuth == nil {
+			c.hostAuth = make(map[string]*hostAuth)
+		}
+		c.hostAuth[strings.ToLower(host)] = &hostAuth{config: auth}
+	}
+}
+
+// authOf returns the credentials configured for the host of the request, or nil if none.
+func (c *Client) authOf(req *http.Request) *hostAuth {
+	if len(c.hostAuth) == 0 {
+		return nil
+	}
+	if auth, ok := c.hostAuth[strings.ToLower(req.URL.Host)]; ok {
+		return auth
+	}
+	return c.hostAuth[strings.ToLower(req.URL.Hostname())]
+}
+
+// authorize adds the credentials of the host of the request, unless the request sets its own Authorization header.
+func (c *Client) authorize(req *http.Request) error {
+	auth := c.authOf(req)
+	if auth == nil || req.Header.Get("Authorization") != "" {
+		return nil
+	}
+
+	cfg := auth.config
+	switch {
+	case cfg.Refresh != nil:
+		token, err := auth.refreshed(req.Context())
+		if err != nil {
+			return fmt.Errorf("fetch: refreshing the token of %s: %w", req.URL.Host, err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	case cfg.Token != "":
+		req.Header.Set("Authorization", "Bearer "+cfg.Token)
+	case cfg.Username != "":
+		req.SetBasicAuth(cfg.Username, cfg.Password)
+	}
+	return nil
+}
+
+// refreshed returns the current token of the host, fetching a new one if it is missing or expired.
+func (a *hostAuth) refreshed(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && (a.expiry.IsZero() || time.Now().Before(a.expiry)) {
+		return a.token, nil
+	}
+
+	token, expiry, err := a.config.Refresh(ctx)
+	if err != nil {
+		return "", err
+	}
+	if token == "" {
+		return "", errors.New("empty token")
+	}
+	a.token, a.expiry = token, expiry
+	return token, nil
+}
+
+// invalidate drops the token of the host refused by a 401 response, so the next request fetches a new one.
+// The token is only dropped if it is the refused one, not one refreshed meanwhile.
+func (a *hostAuth) invalidate(req *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token != "" && req.Header.Get("Authorization") == "Bearer "+a.token {
+		a.token = ""
+	}
+}
+
+// unauthorized invalidates the refreshed token a 401 response was sent for.
+func (c *Client) unauthorized(resp *http.Response) {
+	if resp.StatusCode != http.StatusUnauthorized {
+		return
+	}
+	if auth := c.authOf(resp.Request); auth != nil && auth.config.Refresh != nil {
+		auth.invalidate(resp.Request)
+	}
+}
+
+// checkRedirect replaces the credentials forwarded from the previous request of a redirect chain,
+// when that request got those of its host, with the credentials of the host redirected to.
+func (c *Client) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+
+	if c.authOf(via[len(via)-1]) != nil {
+		req.Header.Del("Authorization")
+	}
+	return c.authorize(req)
+}
@@ -0,0 +1,146 @@
+package fetch_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ricardocastanho/scrapify/fetch"
+)
+
+// authEcho serves the Authorization header of the requests, redirecting /redirect to the given URL.
+func authEcho(t *testing.T, redirect string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, redirect, http.StatusFound)
+			return
+		}
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// host returns the host and port of the server.
+func host(srv *httptest.Server) string {
+	return strings.TrimPrefix(srv.URL, "http://")
+}
+
+// authorization fetches the URL and returns the Authorization header its server received.
+func authorization(t *testing.T, client *fetch.Client, url string) string {
+	t.Helper()
+
+	resp, err := client.Get(context.Background(), url)
+	if err != nil {
+		t.Fatalf("Get %s: %v", url, err)
+	}
+	return string(resp.Body)
+}
+
+func TestHostAuthPerHost(t *testing.T) {
+	open := authEcho(t, "")
+	bearer := authEcho(t, "")
+	basic := authEcho(t, bearer.URL)
+	client := fetch.New(
+		fetch.WithHostAuth(host(basic), fetch.AuthConfig{Username: "user", Password: "secret"}),
+		fetch.WithHostAuth(host(bearer), fetch.AuthConfig{Token: "token"}),
+	)
+
+	if got := authorization(t, client, basic.URL); got != "Basic dXNlcjpzZWNyZXQ=" {
+		t.Errorf("basic host got %q", got)
+	}
+	if got := authorization(t, client, bearer.URL); got != "Bearer token" {
+		t.Errorf("bearer host got %q", got)
+	}
+	if got := authorization(t, client, open.URL); got != "" {
+		t.Errorf("host without credentials got %q", got)
+	}
+	if got := authorization(t, client, basic.URL+"/redirect"); got != "Bearer token" {
+		t.Errorf("host redirected to got %q, want its own credentials", got)
+	}
+}
+
+func TestHostAuthNotForwardedOnRedirect(t *testing.T) {
+	open := authEcho(t, "")
+	basic := authEcho(t, open.URL)
+	client := fetch.New(fetch.WithHostAuth(host(basic), fetch.AuthConfig{Username: "user", Password: "secret"}))
+
+	if got := authorization(t, client, basic.URL+"/redirect"); got != "" {
+		t.Errorf("host redirected to got %q, want no credentials", got)
+	}
+}
+
+func TestHostAuthRefreshesExpiredAndRejectedTokens(t *testing.T) {
+	var refreshes atomic.Int64
+	rejected := "Bearer token-1"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got == rejected {
+			rejected = ""
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer srv.Close()
+
+	client := fetch.New(fetch.WithHostAuth(host(srv), fetch.AuthConfig{
+		Refresh: func(ctx context.Context) (string, time.Time, error) {
+			return fmt.Sprintf("token-%d", refreshes.Add(1)), time.Time{}, nil
+		},
+	}))
+
+	resp, err := client.Get(context.Background(), srv.URL)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("first request: %v, %v; want a 401", resp, err)
+	}
+	if got := authorization(t, client, srv.URL); got != "Bearer token-2" {
+		t.Errorf("after a 401 got %q, want a refreshed token", got)
+	}
+	if got := authorization(t, client, srv.URL); got != "Bearer token-2" {
+		t.Errorf("got %q, want the token reused", got)
+	}
+}
+
+func TestHostAuthRefreshesExpiredTokens(t *testing.T) {
+	srv := authEcho(t, "")
+	var refreshes atomic.Int64
+	client := fetch.New(fetch.WithHostAuth(host(srv), fetch.AuthConfig{
+		Refresh: func(ctx context.Context) (string, time.Time, error) {
+			return fmt.Sprintf("token-%d", refreshes.Add(1)), time.Now().Add(-time.Second), nil
+		},
+	}))
+
+	authorization(t, client, srv.URL)
+	if got := authorization(t, client, srv.URL); got != "Bearer token-2" {
+		t.Errorf("after expiry got %q, want a refreshed token", got)
+	}
+}
+
+func TestHostAuthRefreshFailureFailsRequest(t *testing.T) {
+	srv := authEcho(t, "")
+	client := fetch.New(fetch.WithHostAuth(host(srv), fetch.AuthConfig{
+		Refresh: func(ctx context.Context) (string, time.Time, error) {
+			return "", time.Time{}, fmt.Errorf("identity provider down")
+		},
+	}))
+
+	if _, err := client.Get(context.Background(), srv.URL); err == nil || !strings.Contains(err.Error(), "identity provider down") {
+		t.Errorf("Get error %v, want the refresh failure", err)
+	}
+}
+
+func TestAuthConfigFormattingRedactsSecrets(t *testing.T) {
+	auth := fetch.AuthConfig{Username: "user", Password: "p4ssw0rd", Token: "t0ken"}
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s"} {
+		if got := fmt.Sprintf(verb, auth); strings.Contains(got, "p4ssw0rd") || strings.Contains(got, "t0ken") {
+			t.Errorf("%s formats as %q, leaking a secret", verb, got)
+		}
+	}
+}
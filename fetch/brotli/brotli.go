@@ -0,0 +1,24 @@
+// Package brotli decodes brotli (br) compressed responses for a fetch.Client with github.com/andybalholm/brotli:
+//
+//	client := fetch.New(brotli.WithBrotli())
+//
+// It is a module of its own, so the scrapify module does not depend on a brotli implementation.
+package brotli
+
+import (
+	"io"
+
+	"github.com/andybalholm/brotli"
+	"github.com/ricardocastanho/scrapify/fetch"
+)
+
+// WithBrotli registers Decode for the br content encoding, which is then advertised in the Accept-Encoding header
+// and decoded transparently, like the built-in gzip and deflate encodings.
+func WithBrotli() fetch.Option {
+	return fetch.WithDecoder("br", Decode)
+}
+
+// Decode decodes a brotli body.
+func Decode(r io.Reader) (io.Reader, error) {
+	return brotli.NewReader(r), nil
+}
@@ -0,0 +1,52 @@
+package brotli_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/ricardocastanho/scrapify/fetch"
+	fetchbrotli "github.com/ricardocastanho/scrapify/fetch/brotli"
+)
+
+func TestWithBrotli(t *testing.T) {
+	const page = "<html><body>compressed with brotli</body></html>"
+	var accepted string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accepted = r.Header.Get("Accept-Encoding")
+		w.Header().Set("Content-Encoding", "br")
+		bw := brotli.NewWriter(w)
+		bw.Write([]byte(page))
+		bw.Close()
+	}))
+	defer srv.Close()
+
+	resp, err := fetch.New(fetchbrotli.WithBrotli()).Get(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(resp.Body) != page {
+		t.Errorf("body %q, want %q", resp.Body, page)
+	}
+	if !strings.Contains(accepted, "br") || !strings.Contains(accepted, "gzip") {
+		t.Errorf("Accept-Encoding %q, want br advertised along with gzip", accepted)
+	}
+	if got := resp.Header.Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding %q, want it removed once decoded", got)
+	}
+}
+
+func TestDecodeRejectsCorruptBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "br")
+		w.Write([]byte("not brotli at all"))
+	}))
+	defer srv.Close()
+
+	if _, err := fetch.New(fetchbrotli.WithBrotli()).Get(context.Background(), srv.URL); err == nil {
+		t.Error("Get succeeded, want the error of the corrupt brotli stream")
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/ricardocastanho/scrapify/fetch/brotli
+
+go 1.23.0
+
+require (
+	github.com/andybalholm/brotli v1.1.1
+	github.com/ricardocastanho/scrapify v0.0.0
+)
+
+replace github.com/ricardocastanho/scrapify => ../..
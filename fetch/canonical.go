@@ -0,0 +1,78 @@
+package fetch
+
+import (
+	"bytes"
+	"html"
+	"mime"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// WithCanonicalLinks reads the <link rel="canonical"> tag of HTML responses into Response.Canonical, resolved against the URL
+// of the response, and records it with scrapify.RecordResponse. The Scraper then marks the canonical URL as visited,
+// so the canonical page is not fetched again, and drops the items of the variants of an item URL already crawled,
+// such as ?ref=... ones, so they collapse to a single one.
+// Only the head of the document is searched. Canonical links are not read by default.
+func WithCanonicalLinks() Option {
+	return func(c *Client) {
+		c.canonicalLinks = true
+	}
+}
+
+var (
+	// linkTag matches a <link> tag.
+	linkTag = regexp.MustCompile(`(?is)<link\b[^>]*>`)
+
+	// tagAttr matches an attribute of a tag, with a quoted or unquoted value.
+	tagAttr = regexp.MustCompile(`(?is)([a-z][a-z0-9-]*)\s*=\s*("[^"]*"|'[^']*'|[^\s"'>]+)`)
+)
+
+// canonicalLink returns the URL of the canonical link of an HTML response, resolved against its URL, or an empty string if none.
+func canonicalLink(resp *Response) string {
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err != nil || mediaType != "text/html" {
+		return ""
+	}
+
+	head := resp.Body
+	if i := bytes.Index(bytes.ToLower(head), []byte("</head")); i >= 0 {
+		head = head[:i]
+	}
+
+	for _, tag := range linkTag.FindAll(head, -1) {
+		var rel, href string
+		for _, attr := range tagAttr.FindAllSubmatch(tag, -1) {
+			value := html.UnescapeString(strings.Trim(string(attr[2]), `"'`))
+			switch strings.ToLower(string(attr[1])) {
+			case "rel":
+				rel = value
+			case "href":
+				href = value
+			}
+		}
+		if href == "" || !hasToken(rel, "canonical") {
+			continue
+		}
+
+		base, err := url.Parse(resp.URL)
+		if err != nil {
+			return ""
+		}
+		ref, err := base.Parse(strings.TrimSpace(href))
+		if err != nil {
+			return ""
+		}
+		return ref.String()
+	}
+	return ""
+}
+
+// hasToken reports whether the space-separated list contains the token, case-insensitively.
+func hasToken(list, token string) bool {
+	for _, t := range strings.Fields(list) {
+		if strings.EqualFold(t, token) {
+			return true
+		}
+	}
+	return false
+}
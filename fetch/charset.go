@@ -0,0 +1,165 @@
+package fetch
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"mime"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// CharsetDecoder transcodes a body in a charset to UTF-8. It must be safe for concurrent use.
+// The fetch/textcharset module adapts the encodings of golang.org/x/text.
+type CharsetDecoder func(body []byte) ([]byte, error)
+
+// charsetSniffLen is how many bytes of an HTML body are searched for a <meta> charset declaration.
+const charsetSniffLen = 1024
+
+// metaTag matches a <meta> tag.
+var metaTag = regexp.MustCompile(`(?is)<meta\b[^>]*>`)
+
+// defaultCharsets returns the charsets supported out of the box, keyed by lowercase label.
+func defaultCharsets() map[string]CharsetDecoder {
+	charsets := map[string]CharsetDecoder{
+		"utf-8": decodeUTF8,
+		"utf8":  decodeUTF8,
+	}
+	// As browsers do, ASCII and Latin-1 labels are decoded as Windows-1252, of which they are subsets.
+	for _, label := range []string{"windows-1252", "cp1252", "iso-8859-1", "iso8859-1", "latin1", "l1", "us-ascii", "ascii"} {
+		charsets[label] = decodeWindows1252
+	}
+	return charsets
+}
+
+// WithCharsetDetection transcodes the bodies of responses to UTF-8 from the charset they declare, so pages in legacy
+// encodings are not parsed as garbled UTF-8. The charset is read from the Content-Type header or, for HTML responses
+// without one, from a <meta charset> or <meta http-equiv="Content-Type"> tag at the start of the document.
+// Transcoded responses have their Content-Type charset set to utf-8. UTF-8, ASCII, ISO-8859-1 and Windows-1252 are built in;
+// other charsets, such as Shift_JIS, need a decoder registered with WithCharset or WithCharsetLookup. The body of a response
+// declaring a charset without a decoder is left untouched, as are all bodies by default.
+func WithCharsetDetection() Option {
+	return func(c *Client) {
+		c.charsetDetection = true
+	}
+}
+
+// WithCharset registers the decoder of a charset for WithCharsetDetection, in addition to the built-in ones.
+// The standard library only covers Unicode, so other charsets need a user-supplied decoder, such as one of
+// golang.org/x/text/encoding adapted by the fetch/textcharset module:
+//
+//	fetch.WithCharset("shift_jis", textcharset.Decoder(japanese.ShiftJIS))
+func WithCharset(name string, decoder CharsetDecoder) Option {
+	return func(c *Client) {
+		c.charsets[strings.ToLower(name)] = decoder
+	}
+}
+
+// WithCharsetLookup registers a function returning the decoder of a charset for WithCharsetDetection, consulted with
+// the lowercase name of the charsets neither built in nor registered with WithCharset. It returns nil for an unknown charset.
+// The fetch/textcharset module provides one covering every charset of the WHATWG Encoding Standard.
+func WithCharsetLookup(lookup func(name string) CharsetDecoder) Option {
+	return func(c *Client) {
+		c.charsetLookup = lookup
+	}
+}
+
+// transcode converts the body of the response to UTF-8 according to the charset it declares.
+// A body in a charset without a decoder is returned as is.
+func (c *Client) transcode(header http.Header, body []byte) ([]byte, error) {
+	name := declaredCharset(header, body)
+	if name == "" {
+		return body, nil
+	}
+
+	decoder, ok := c.charsets[name]
+	if !ok && c.charsetLookup != nil {
+		decoder = c.charsetLookup(name)
+	}
+	if decoder == nil {
+		return body, nil
+	}
+	decoded, err := decoder(body)
+	if err != nil {
+		return nil, fmt.Errorf("fetch: decoding %s body: %w", name, err)
+	}
+
+	if mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type")); err == nil {
+		params["charset"] = "utf-8"
+		header.Set("Content-Type", mime.FormatMediaType(mediaType, params))
+	}
+	return decoded, nil
+}
+
+// declaredCharset returns the lowercase charset declared by a response, or an empty string if none.
+// A UTF-8 byte order mark wins over the Content-Type header, which wins over a <meta> tag of an HTML document.
+func declaredCharset(header http.Header, body []byte) string {
+	if bytes.HasPrefix(body, utf8BOM) {
+		return "utf-8"
+	}
+
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err == nil && params["charset"] != "" {
+		return strings.ToLower(strings.Trim(params["charset"], `"' `))
+	}
+	if err != nil || mediaType != "text/html" {
+		return ""
+	}
+
+	head := body[:min(len(body), charsetSniffLen)]
+	for _, tag := range metaTag.FindAll(head, -1) {
+		var charset, httpEquiv, content string
+		for _, attr := range tagAttr.FindAllSubmatch(tag, -1) {
+			value := html.UnescapeString(strings.Trim(string(attr[2]), `"'`))
+			switch strings.ToLower(string(attr[1])) {
+			case "charset":
+				charset = value
+			case "http-equiv":
+				httpEquiv = value
+			case "content":
+				content = value
+			}
+		}
+
+		if charset != "" {
+			return strings.ToLower(strings.TrimSpace(charset))
+		}
+		if strings.EqualFold(httpEquiv, "content-type") {
+			if _, params, err := mime.ParseMediaType(content); err == nil && params["charset"] != "" {
+				return strings.ToLower(params["charset"])
+			}
+		}
+	}
+	return ""
+}
+
+// utf8BOM is the byte order mark starting some UTF-8 documents.
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// decodeUTF8 decodes a UTF-8 body, which only needs its byte order mark removed.
+func decodeUTF8(body []byte) ([]byte, error) {
+	return bytes.TrimPrefix(body, utf8BOM), nil
+}
+
+// windows1252 maps the bytes 0x80 to 0x9f of Windows-1252 to their runes; the other bytes map to the same code point.
+var windows1252 = [32]rune{
+	'\u20ac', '\u0081', '\u201a', '\u0192', '\u201e', '\u2026', '\u2020', '\u2021',
+	'\u02c6', '\u2030', '\u0160', '\u2039', '\u0152', '\u008d', '\u017d', '\u008f',
+	'\u0090', '\u2018', '\u2019', '\u201c', '\u201d', '\u2022', '\u2013', '\u2014',
+	'\u02dc', '\u2122', '\u0161', '\u203a', '\u0153', '\u009d', '\u017e', '\u0178',
+}
+
+// decodeWindows1252 decodes a Windows-1252 body, one rune per byte.
+func decodeWindows1252(body []byte) ([]byte, error) {
+	decoded := make([]byte, 0, len(body))
+	for _, b := range body {
+		r := rune(b)
+		if b >= 0x80 && b < 0xa0 {
+			r = windows1252[b-0x80]
+		}
+		decoded = utf8.AppendRune(decoded, r)
+	}
+	return decoded, nil
+}
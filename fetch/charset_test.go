@@ -0,0 +1,82 @@
+package fetch_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ricardocastanho/scrapify/fetch"
+)
+
+// shiftJIS is a Shift_JIS page titled 日本語 ("Japanese"), declaring its charset in a <meta> tag.
+const shiftJIS = "<html><head><meta charset=\"Shift_JIS\"><title>\x93\xfa\x96\x7b\x8c\xea</title></head></html>"
+
+// serve serves body with the given Content-Type header.
+func serve(t *testing.T, contentType, body string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCharsetDetection(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{"header", "text/plain; charset=ISO-8859-1", "caf\xe9", "café"},
+		{"header over meta", "text/html; charset=windows-1252", `<meta charset="utf-8">` + "\x93quoted\x94", `<meta charset="utf-8">“quoted”`},
+		{"http-equiv", "text/html", `<meta http-equiv="Content-Type" content="text/html; charset=latin1">` + "\xfcber", `<meta http-equiv="Content-Type" content="text/html; charset=latin1">über`},
+		{"byte order mark", "text/html; charset=latin1", "\xef\xbb\xbfcafé", "café"},
+		{"undeclared", "text/html", "caf\xe9", "caf\xe9"},
+		{"meta outside html", "text/plain", `<meta charset="latin1">` + "caf\xe9", `<meta charset="latin1">` + "caf\xe9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := serve(t, tt.contentType, tt.body)
+
+			resp, err := fetch.New(fetch.WithCharsetDetection()).Get(context.Background(), srv.URL)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if string(resp.Body) != tt.want {
+				t.Errorf("body %q, want %q", resp.Body, tt.want)
+			}
+		})
+	}
+}
+
+func TestCharsetWithoutDecoder(t *testing.T) {
+	srv := serve(t, "text/html; charset=Shift_JIS", shiftJIS)
+	client := fetch.New(fetch.WithCharsetDetection(), fetch.WithCharsetLookup(func(name string) fetch.CharsetDecoder { return nil }))
+
+	resp, err := client.Get(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(resp.Body) != shiftJIS {
+		t.Errorf("body %q, want it untouched", resp.Body)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "text/html; charset=Shift_JIS" {
+		t.Errorf("Content-Type %q, want the declared charset", got)
+	}
+}
+
+func TestCharsetDetectionIsOptIn(t *testing.T) {
+	srv := serve(t, "text/html", shiftJIS)
+
+	resp, err := fetch.New().Get(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(resp.Body) != shiftJIS {
+		t.Errorf("body %q, want it untouched", resp.Body)
+	}
+}
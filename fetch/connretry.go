@@ -0,0 +1,55 @@
+package fetch
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// WithConnectionRetry resends a request up to n times, immediately, when it fails with a connection error before any response
+// is received: a connection reset or aborted by the peer, a connection closed early (EOF) or a temporary DNS failure.
+// These are usually transient, such as a stale keep-alive connection closed by the server, and not worth the backoff of
+// scrapify.WithRetry. Responses are never retried, whatever their status, and neither are timeouts nor cancelled requests,
+// so these retries do not show in the retry counts of the crawl. Requests with a body are only resent when their body can be
+// rewound, as it can for the bodies given to http.NewRequest as a bytes.Reader, bytes.Buffer or strings.Reader.
+// The default of zero disables connection retries.
+func WithConnectionRetry(n int) Option {
+	return func(c *Client) {
+		c.connRetries = n
+	}
+}
+
+// send sends the request, resending it on the connection errors of WithConnectionRetry.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.http.Do(req)
+		if err == nil || attempt >= c.connRetries || req.Context().Err() != nil || !connectionError(err) {
+			return resp, err
+		}
+
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return nil, err
+			}
+			body, berr := req.GetBody()
+			if berr != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+// connectionError reports whether the error is a transient failure of the connection rather than of the request.
+func connectionError(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var dns *net.DNSError
+	return errors.As(err, &dns) && dns.IsTemporary && !dns.IsTimeout
+}
@@ -0,0 +1,104 @@
+package fetch
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// Decoder wraps a compressed response body with a reader returning the decoded content.
+type Decoder func(r io.Reader) (io.Reader, error)
+
+// defaultDecoders returns the decoders supported out of the box.
+func defaultDecoders() map[string]Decoder {
+	return map[string]Decoder{
+		"gzip":    decodeGzip,
+		"deflate": decodeDeflate,
+	}
+}
+
+// WithDecoder registers a decoder for a content encoding, in addition to the built-in gzip and deflate support.
+// Registered encodings are advertised in the Accept-Encoding header. Brotli is not built in, since the standard library
+// has no decoder for it and this package has no dependencies: it needs a user-supplied decoder, such as the one the
+// fetch/brotli module registers with github.com/andybalholm/brotli:
+//
+//	fetch.New(brotli.WithBrotli())
+func WithDecoder(encoding string, decoder Decoder) Option {
+	return func(c *Client) {
+		c.decoders[strings.ToLower(encoding)] = decoder
+	}
+}
+
+// decodeGzip decodes a gzip body.
+func decodeGzip(r io.Reader) (io.Reader, error) {
+	return gzip.NewReader(r)
+}
+
+// decodeDeflate decodes a deflate body. The HTTP deflate encoding is zlib-wrapped,
+// but some servers send a raw deflate stream, so both are accepted.
+func decodeDeflate(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+
+	// A zlib stream starts with a compression method of 8 and a header checksum divisible by 31.
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
+
+// acceptEncoding returns the value of the Accept-Encoding header advertising the registered decoders.
+func (c *Client) acceptEncoding() string {
+	encodings := make([]string, 0, len(c.decoders))
+	for encoding := range c.decoders {
+		encodings = append(encodings, encoding)
+	}
+	slices.Sort(encodings)
+	return strings.Join(encodings, ", ")
+}
+
+// decode returns a reader decoding the response body according to its Content-Encoding header.
+// Encodings are undone in the reverse order they were applied. The encoding headers are removed from the response,
+// since they no longer describe the body.
+func (c *Client) decode(resp *http.Response) (io.Reader, error) {
+	var r io.Reader = resp.Body
+	if resp.ContentLength == 0 || resp.Request.Method == http.MethodHead {
+		return r, nil
+	}
+
+	var encodings []string
+	for _, v := range resp.Header.Values("Content-Encoding") {
+		for _, encoding := range strings.Split(v, ",") {
+			if encoding = strings.ToLower(strings.TrimSpace(encoding)); encoding != "" && encoding != "identity" {
+				encodings = append(encodings, encoding)
+			}
+		}
+	}
+
+	for i := len(encodings) - 1; i >= 0; i-- {
+		decoder, ok := c.decoders[encodings[i]]
+		if !ok {
+			return nil, fmt.Errorf("fetch: unsupported content encoding %q", encodings[i])
+		}
+
+		var err error
+		if r, err = decoder(r); err != nil {
+			return nil, fmt.Errorf("fetch: decoding %s body: %w", encodings[i], err)
+		}
+	}
+
+	if len(encodings) > 0 {
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+	}
+	return r, nil
+}
@@ -0,0 +1,137 @@
+package fetch_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ricardocastanho/scrapify/fetch"
+)
+
+const page = "<html><body>compressed page</body></html>"
+
+// compressed serves page compressed by compress under the given content encoding, recording the Accept-Encoding of the requests.
+func compressed(t *testing.T, encoding string, compress func(w io.Writer) io.WriteCloser, accepted *string) *httptest.Server {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := compress(&buf)
+	if _, err := io.WriteString(w, page); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if accepted != nil {
+			*accepted = r.Header.Get("Accept-Encoding")
+		}
+		w.Header().Set("Content-Encoding", encoding)
+		w.Write(buf.Bytes())
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func gzipWriter(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }
+
+func TestGetDecodesGzip(t *testing.T) {
+	var accepted string
+	srv := compressed(t, "gzip", gzipWriter, &accepted)
+
+	resp, err := fetch.New().Get(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(resp.Body) != page {
+		t.Errorf("Body = %q, want %q", resp.Body, page)
+	}
+	if !strings.Contains(accepted, "gzip") {
+		t.Errorf("Accept-Encoding = %q, want gzip advertised", accepted)
+	}
+	if got := resp.Header.Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q after decoding, want it removed", got)
+	}
+}
+
+func TestDoDecodesGzipWithCustomAcceptEncoding(t *testing.T) {
+	var accepted string
+	srv := compressed(t, "gzip", gzipWriter, &accepted)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept-Encoding", "gzip;q=1.0")
+	resp, err := fetch.New().Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if accepted != "gzip;q=1.0" {
+		t.Errorf("Accept-Encoding = %q, want the one set by the request", accepted)
+	}
+	if string(resp.Body) != page {
+		t.Errorf("Body = %q, want %q", resp.Body, page)
+	}
+}
+
+func TestGetDecodesDeflate(t *testing.T) {
+	for name, compress := range map[string]func(w io.Writer) io.WriteCloser{
+		"zlib": func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) },
+		"raw": func(w io.Writer) io.WriteCloser {
+			fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+			return fw
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			srv := compressed(t, "deflate", compress, nil)
+
+			resp, err := fetch.New().Get(context.Background(), srv.URL)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if string(resp.Body) != page {
+				t.Errorf("Body = %q, want %q", resp.Body, page)
+			}
+		})
+	}
+}
+
+func TestGetFailsOnBrotliWithoutDecoder(t *testing.T) {
+	var accepted string
+	srv := compressed(t, "br", gzipWriter, &accepted)
+
+	_, err := fetch.New().Get(context.Background(), srv.URL)
+	if err == nil || !strings.Contains(err.Error(), `"br"`) {
+		t.Errorf("Get = %v, want an unsupported br encoding error", err)
+	}
+	if strings.Contains(accepted, "br") {
+		t.Errorf("Accept-Encoding = %q, want br not advertised without a decoder", accepted)
+	}
+}
+
+func TestWithDecoderAddsEncoding(t *testing.T) {
+	var accepted string
+	// The test stands in for brotli with gzip, under the br encoding.
+	srv := compressed(t, "br", gzipWriter, &accepted)
+	client := fetch.New(fetch.WithDecoder("br", func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }))
+
+	resp, err := client.Get(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(resp.Body) != page {
+		t.Errorf("Body = %q, want %q", resp.Body, page)
+	}
+	if !strings.Contains(accepted, "br") {
+		t.Errorf("Accept-Encoding = %q, want br advertised", accepted)
+	}
+}
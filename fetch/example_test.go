@@ -0,0 +1,36 @@
+package fetch_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/ricardocastanho/scrapify/fetch"
+)
+
+func ExampleWithDialer() {
+	// A test server standing in for shop.example, which reports the host it was asked for.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "served %s%s", r.Host, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	// Pin shop.example to the test server, leaving every other host to the regular dialer.
+	var dialer net.Dialer
+	client := fetch.New(fetch.WithDialer(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if addr == "shop.example:80" {
+			addr = srv.Listener.Addr().String()
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}))
+
+	resp, err := client.Get(context.Background(), "http://shop.example/products")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(string(resp.Body))
+	// Output: served shop.example/products
+}
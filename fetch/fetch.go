@@ -0,0 +1,206 @@
+// Package fetch provides an HTTP client tuned for crawling, meant to be used by scrapify.IScraper implementations.
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+// Client performs HTTP requests on behalf of a scraper.
+// It is safe for concurrent use and should be shared by all the scrapers of a crawl so connections are reused.
+type Client struct {
+	http             *http.Client                  // Underlying HTTP client.
+	transport        TransportConfig               // Connection settings used to build the transport.
+	decoders         map[string]Decoder            // Decoders of compressed bodies, keyed by content encoding.
+	charsets         map[string]CharsetDecoder     // Decoders of body charsets to UTF-8, keyed by lowercase label.
+	charsetLookup    func(string) CharsetDecoder   // Decoders of the charsets missing from charsets (may be nil).
+	charsetDetection bool                          // Whether bodies are transcoded to UTF-8, see WithCharsetDetection.
+	archive          string                        // Directory where responses are archived (empty disables archiving).
+	fingerprints     []HeaderSet                   // Browser fingerprints rotated across requests (empty disables them).
+	connRetries      int                           // Immediate retries on connection errors, see WithConnectionRetry.
+	dial             DialFunc                      // Opens the connections of the transport (nil uses the standard library dialer).
+	parsers          map[string]Parser             // Parsers of the responses, keyed by content type, see WithParser.
+	canonicalLinks   bool                          // Whether the canonical links of HTML responses are read.
+	onPage           func(url string, body []byte) // Hook invoked with every fetched body (may be nil).
+	hostAuth         map[string]*hostAuth          // Credentials sent to each host, keyed by lowercase host, see WithHostAuth.
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// Response is a fully read HTTP response.
+type Response struct {
+	URL           string              // Final URL of the response, after redirects.
+	StatusCode    int                 // HTTP status code.
+	Header        http.Header         // Response headers.
+	Body          []byte              // Response body.
+	Redirects     []string            // URLs redirected before reaching URL, starting with the requested one (nil without redirects).
+	RedirectChain []scrapify.Redirect // Redirect responses followed before reaching URL, oldest first (nil without redirects).
+	Canonical     string              // URL of the canonical link of an HTML response, with WithCanonicalLinks (empty if none).
+}
+
+// New creates a Client with the given options.
+func New(opts ...Option) *Client {
+	c := &Client{
+		transport: DefaultTransportConfig(),
+		decoders:  defaultDecoders(),
+		charsets:  defaultCharsets(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.http = &http.Client{Transport: c.transport.build(c.dial)}
+	if len(c.hostAuth) > 0 {
+		c.http.CheckRedirect = c.checkRedirect
+	}
+	return c
+}
+
+// Get fetches the URL with a GET request.
+// When the context carries a scrapify.Request for this URL, as it does for the start page of a strategy
+// defining one, that request's method, headers and body are used instead.
+func (c *Client) Get(ctx context.Context, url string) (*Response, error) {
+	method, header, body := http.MethodGet, http.Header(nil), io.Reader(nil)
+	if r, ok := scrapify.RequestFromContext(ctx); ok && r.URL == url {
+		method, header = r.Method, r.Header
+		if r.Body != nil {
+			body = bytes.NewReader(r.Body)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	return c.Do(req)
+}
+
+// Do sends the request and reads the whole response body.
+// With WithHeaderFingerprints, the headers of a random browser fingerprint are added first.
+// Compressed bodies are decoded transparently; unless the request sets its own Accept-Encoding header,
+// every supported encoding is advertised. Bodies are decoded even when a custom Accept-Encoding is set.
+// With WithCharsetDetection, bodies are then transcoded to UTF-8.
+// With WithHostAuth, the credentials of the host are added.
+// With WithConnectionRetry, requests failing with a connection error are resent immediately.
+// An error is only returned when the request could not be completed; non-2xx responses are returned as is.
+// Redirects are followed as by the standard library client, and their hops reported in Response.RedirectChain;
+// a chain that is not followed to its end, such as a redirect loop, fails with a RedirectError holding its hops.
+// The response is recorded with scrapify.RecordResponse, so it appears in the ItemMeta of the items scraped from it.
+func (c *Client) Do(req *http.Request) (*Response, error) {
+	c.fingerprint(req)
+	if req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", c.acceptEncoding())
+	}
+	if err := c.authorize(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.send(req)
+	if err != nil {
+		if resp != nil {
+			return nil, &RedirectError{Chain: stoppedChain(resp), Err: err}
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+	c.unauthorized(resp)
+
+	r, err := c.decode(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if c.charsetDetection {
+		if body, err = c.transcode(resp.Header, body); err != nil {
+			return nil, err
+		}
+	}
+
+	res := &Response{
+		URL:           resp.Request.URL.String(),
+		StatusCode:    resp.StatusCode,
+		Header:        resp.Header,
+		Body:          body,
+		Redirects:     redirects(resp),
+		RedirectChain: redirectChain(resp),
+	}
+	if c.canonicalLinks {
+		res.Canonical = canonicalLink(res)
+	}
+	if c.onPage != nil {
+		c.onPage(res.URL, res.Body)
+	}
+	scrapify.RecordResponse(req.Context(), scrapify.ResponseInfo{URL: res.URL, StatusCode: res.StatusCode, Redirects: res.Redirects, RedirectChain: res.RedirectChain, Bytes: int64(len(body)), Canonical: res.Canonical})
+	if c.archive != "" {
+		if err := c.store(req, res); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// redirects returns the URLs of the requests redirected before the one that produced the response, oldest first.
+func redirects(resp *http.Response) []string {
+	var urls []string
+	for r := resp.Request.Response; r != nil; r = r.Request.Response {
+		urls = append(urls, r.Request.URL.String())
+	}
+	slices.Reverse(urls)
+	return urls
+}
+
+// RedirectError reports a redirect chain that was not followed to its end, such as a redirect loop or a chain longer
+// than the 10 redirects followed, along with its hops, to tell loops from chains that are merely too long.
+type RedirectError struct {
+	Chain []scrapify.Redirect // Redirect responses received, oldest first; the redirect of the last one was not followed.
+	Err   error               // Why the last redirect was not followed.
+}
+
+// Error describes the redirect chain and why it was stopped.
+func (e *RedirectError) Error() string {
+	return fmt.Sprintf("fetch: redirect chain stopped after %d hops: %v", len(e.Chain), e.Err)
+}
+
+// Unwrap returns why the last redirect was not followed.
+func (e *RedirectError) Unwrap() error {
+	return e.Err
+}
+
+// stoppedChain returns the redirect chain ending with resp, the redirect response the client refused to follow.
+func stoppedChain(resp *http.Response) []scrapify.Redirect {
+	hop := scrapify.Redirect{URL: resp.Request.URL.String(), StatusCode: resp.StatusCode}
+	if location, err := resp.Location(); err == nil {
+		hop.Location = location.String()
+	}
+	return append(redirectChain(resp), hop)
+}
+
+// redirectChain returns the redirect responses followed before the one given, oldest first.
+// The standard library client links each request of a redirect chain to the response it follows, so every hop
+// is recovered from the final response as is, without changing how redirects are followed.
+func redirectChain(resp *http.Response) []scrapify.Redirect {
+	var chain []scrapify.Redirect
+	for next := resp.Request; next.Response != nil; next = next.Response.Request {
+		chain = append(chain, scrapify.Redirect{
+			URL:        next.Response.Request.URL.String(),
+			StatusCode: next.Response.StatusCode,
+			Location:   next.URL.String(),
+		})
+	}
+	slices.Reverse(chain)
+	return chain
+}
@@ -0,0 +1,75 @@
+package fetch
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"slices"
+)
+
+// HeaderSet is a consistent set of request headers sent by a real browser: its User-Agent together with
+// the Accept, Accept-Language and client hint headers that go with it.
+type HeaderSet http.Header
+
+// WithHeaderFingerprints picks a HeaderSet of pool at random for every request and sends its headers,
+// so the crawl rotates complete browser fingerprints instead of the User-Agent alone.
+// Headers set on the request itself, including those of a scrapify.Request, take precedence over the fingerprint.
+// The headers are sent in the canonical order of net/http, whatever the browser they come from.
+// DefaultHeaderSets returns a few realistic fingerprints. An empty pool disables fingerprints.
+func WithHeaderFingerprints(pool []HeaderSet) Option {
+	return func(c *Client) {
+		c.fingerprints = pool
+	}
+}
+
+// DefaultHeaderSets returns the fingerprints of current desktop versions of Chrome on Windows, Firefox on Linux and Safari on macOS.
+// Their Accept-Encoding is left to the Client, which advertises the encodings it can decode.
+func DefaultHeaderSets() []HeaderSet {
+	return []HeaderSet{
+		{
+			"User-Agent":                {"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"},
+			"Accept":                    {"text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7"},
+			"Accept-Language":           {"en-US,en;q=0.9"},
+			"Sec-Ch-Ua":                 {`"Chromium";v="124", "Google Chrome";v="124", "Not-A.Brand";v="99"`},
+			"Sec-Ch-Ua-Mobile":          {"?0"},
+			"Sec-Ch-Ua-Platform":        {`"Windows"`},
+			"Sec-Fetch-Dest":            {"document"},
+			"Sec-Fetch-Mode":            {"navigate"},
+			"Sec-Fetch-Site":            {"none"},
+			"Sec-Fetch-User":            {"?1"},
+			"Upgrade-Insecure-Requests": {"1"},
+		},
+		{
+			"User-Agent":                {"Mozilla/5.0 (X11; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0"},
+			"Accept":                    {"text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8"},
+			"Accept-Language":           {"en-US,en;q=0.5"},
+			"Sec-Fetch-Dest":            {"document"},
+			"Sec-Fetch-Mode":            {"navigate"},
+			"Sec-Fetch-Site":            {"none"},
+			"Sec-Fetch-User":            {"?1"},
+			"Upgrade-Insecure-Requests": {"1"},
+		},
+		{
+			"User-Agent":      {"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15"},
+			"Accept":          {"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"},
+			"Accept-Language": {"en-US,en;q=0.9"},
+			"Sec-Fetch-Dest":  {"document"},
+			"Sec-Fetch-Mode":  {"navigate"},
+			"Sec-Fetch-Site":  {"none"},
+		},
+	}
+}
+
+// fingerprint adds the headers of a random HeaderSet of the pool to the request, unless the request already sets them.
+func (c *Client) fingerprint(req *http.Request) {
+	if len(c.fingerprints) == 0 {
+		return
+	}
+
+	set := c.fingerprints[rand.IntN(len(c.fingerprints))]
+	for k, v := range set {
+		k = http.CanonicalHeaderKey(k)
+		if _, ok := req.Header[k]; !ok {
+			req.Header[k] = slices.Clone(v)
+		}
+	}
+}
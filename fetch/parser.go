@@ -0,0 +1,50 @@
+package fetch
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// ErrNoParser is returned by Parse when no parser is registered for the content type of the response.
+var ErrNoParser = errors.New("fetch: no parser for content type")
+
+// Parser extracts a value from a response, such as a parsed HTML document or a decoded JSON payload.
+type Parser func(resp *Response) (any, error)
+
+// WithParser registers the parser of the responses of a content type, such as "application/json", for Parse.
+// The content type may be a media type, a wildcard for all the subtypes of a type such as "text/*",
+// or "*/*" for the default parser of the content types without a more specific one.
+// Content types are compared without their parameters and case-insensitively.
+func WithParser(contentType string, parser Parser) Option {
+	return func(c *Client) {
+		if c.parsers == nil {
+			c.parsers = make(map[string]Parser)
+		}
+		c.parsers[strings.ToLower(contentType)] = parser
+	}
+}
+
+// Parse passes the response to the parser registered with WithParser for its Content-Type header, to handle crawls
+// mixing HTML, JSON and XML endpoints in one scraper. The parser of the exact media type is preferred, then the one of
+// its type wildcard and then the default one. It returns ErrNoParser when none applies.
+func (c *Client) Parse(resp *Response) (any, error) {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil {
+		mediaType = ""
+	}
+
+	candidates := []string{mediaType}
+	if typ, _, ok := strings.Cut(mediaType, "/"); ok {
+		candidates = append(candidates, typ+"/*")
+	}
+	candidates = append(candidates, "*/*")
+
+	for _, contentType := range candidates {
+		if parser, ok := c.parsers[contentType]; ok && contentType != "" {
+			return parser(resp)
+		}
+	}
+	return nil, fmt.Errorf("%w %q", ErrNoParser, resp.Header.Get("Content-Type"))
+}
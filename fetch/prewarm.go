@@ -0,0 +1,50 @@
+package fetch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// Prewarm opens a connection to the origin of each URL, TLS handshake included, and leaves it idle in the pool of the Client,
+// so the first requests to these hosts do not wait for a connection. Each distinct origin is prewarmed once, concurrently,
+// with a HEAD request on its root, whose response is discarded. It is best-effort: failures are ignored.
+// It returns once every connection is open or has failed. It has the signature of scrapify.Prewarmer, so scrapers can forward WithConnectionPrewarm to it:
+//
+//	func (s *MyScraper) Prewarm(ctx context.Context, urls []string) { s.client.Prewarm(ctx, urls) }
+func (c *Client) Prewarm(ctx context.Context, urls []string) {
+	var wg sync.WaitGroup
+	origins := make(map[string]bool)
+	for _, rawURL := range urls {
+		u, err := url.Parse(rawURL)
+		if err != nil || u.Host == "" || origins[u.Scheme+"://"+u.Host] {
+			continue
+		}
+		origin := u.Scheme + "://" + u.Host
+		origins[origin] = true
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.warm(ctx, origin)
+		}()
+	}
+	wg.Wait()
+}
+
+// warm sends a HEAD request on the root of the origin and releases its connection to the pool.
+func (c *Client) warm(ctx context.Context, origin string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, origin+"/", nil)
+	if err != nil {
+		return
+	}
+	c.fingerprint(req)
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
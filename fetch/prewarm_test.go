@@ -0,0 +1,49 @@
+package fetch_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ricardocastanho/scrapify/fetch"
+)
+
+func TestPrewarmOpensOneReusedConnectionPerHost(t *testing.T) {
+	var conns, heads atomic.Int64
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			heads.Add(1)
+		}
+	}))
+	srv.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	client := fetch.New()
+	client.Prewarm(context.Background(), []string{srv.URL + "/a", srv.URL + "/b", "not a url\x00"})
+	if conns.Load() != 1 || heads.Load() != 1 {
+		t.Fatalf("prewarming opened %d connections with %d requests, want 1 and 1", conns.Load(), heads.Load())
+	}
+
+	if _, err := client.Get(context.Background(), srv.URL+"/a"); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if conns.Load() != 1 {
+		t.Errorf("%d connections after the first request, want the prewarmed one reused", conns.Load())
+	}
+}
+
+func TestPrewarmIgnoresFailures(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	// A closed server only makes prewarming fail, silently.
+	fetch.New().Prewarm(context.Background(), []string{srv.URL})
+}
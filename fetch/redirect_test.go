@@ -0,0 +1,106 @@
+package fetch_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/ricardocastanho/scrapify"
+	"github.com/ricardocastanho/scrapify/fetch"
+)
+
+// redirector serves /final, redirects /moved permanently to /found and /found temporarily to /final,
+// and redirects /loop to itself.
+func redirector(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/moved":
+			http.Redirect(w, r, "/found", http.StatusMovedPermanently)
+		case "/found":
+			http.Redirect(w, r, "/final", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRedirectChain(t *testing.T) {
+	srv := redirector(t)
+
+	var meta scrapify.ItemMeta
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+		Scraper: redirectScraper{client: fetch.New()},
+		Url:     srv.URL,
+	}}, nil, 0, scrapify.OnItemMeta(func(item string, m scrapify.ItemMeta) { meta = m }))
+	if err := scraper.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	want := []scrapify.Redirect{
+		{URL: srv.URL + "/moved", StatusCode: http.StatusMovedPermanently, Location: srv.URL + "/found"},
+		{URL: srv.URL + "/found", StatusCode: http.StatusFound, Location: srv.URL + "/final"},
+	}
+	if !slices.Equal(meta.RedirectChain, want) {
+		t.Errorf("ItemMeta.RedirectChain = %v, want %v", meta.RedirectChain, want)
+	}
+	if meta.FinalURL != srv.URL+"/final" || meta.StatusCode != http.StatusOK {
+		t.Errorf("final response %s with status %d", meta.FinalURL, meta.StatusCode)
+	}
+}
+
+func TestRedirectChainEmptyWithoutRedirects(t *testing.T) {
+	srv := redirector(t)
+
+	resp, err := fetch.New().Get(context.Background(), srv.URL+"/final")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if resp.RedirectChain != nil {
+		t.Errorf("RedirectChain = %v, want nil", resp.RedirectChain)
+	}
+}
+
+func TestRedirectLoopReportsItsChain(t *testing.T) {
+	srv := redirector(t)
+
+	_, err := fetch.New().Get(context.Background(), srv.URL+"/loop")
+	var redirectErr *fetch.RedirectError
+	if !errors.As(err, &redirectErr) {
+		t.Fatalf("Get error %v, want a RedirectError", err)
+	}
+	if n := len(redirectErr.Chain); n != 10 {
+		t.Errorf("chain of %d hops, want the 10 followed", n)
+	}
+	for _, hop := range redirectErr.Chain {
+		if hop.URL != srv.URL+"/loop" || hop.Location != srv.URL+"/loop" || hop.StatusCode != http.StatusFound {
+			t.Errorf("hop %+v, want /loop redirecting to itself", hop)
+		}
+	}
+}
+
+// redirectScraper fetches /moved under its start page and sends its final URL as an item.
+type redirectScraper struct {
+	client *fetch.Client
+}
+
+// GetUrls discovers the /moved item URL.
+func (r redirectScraper) GetUrls(ctx context.Context, url string) ([]string, []string, error) {
+	return []string{url + "/moved"}, nil, nil
+}
+
+// GetData fetches the item URL and sends its final URL.
+func (r redirectScraper) GetData(ctx context.Context, ch chan<- string, data *string, url string) error {
+	resp, err := r.client.Get(ctx, url)
+	if err != nil {
+		return err
+	}
+	ch <- resp.URL
+	return nil
+}
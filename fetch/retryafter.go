@@ -0,0 +1,35 @@
+package fetch
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryAfter parses the Retry-After header of the response, given either in seconds or as an HTTP date,
+// and returns how long to wait before the next request. It returns false when the header is missing or invalid.
+// Pass the delay to scrapify.RetryAfter to back off the host of a 429 or 503 response:
+//
+//	if d, ok := fetch.RetryAfter(resp); ok && resp.StatusCode == http.StatusTooManyRequests {
+//		return scrapify.RetryAfter(d, scrapify.ErrRateLimited)
+//	}
+func RetryAfter(resp *Response) (time.Duration, bool) {
+	v := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+	return max(time.Until(t), 0), true
+}
@@ -0,0 +1,93 @@
+package fetch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+// RobotsCrawlDelay returns a lookup for scrapify.WithCrawlDelay reading the Crawl-delay directive of each origin's robots.txt with the client.
+// The directive of the group matching userAgent is used, falling back to the one of the "*" group.
+// Origins without a robots.txt, or whose robots.txt has no Crawl-delay for the user agent, have no delay.
+func RobotsCrawlDelay(c *Client, userAgent string) scrapify.CrawlDelayLookup {
+	return func(ctx context.Context, origin string) (time.Duration, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+"/robots.txt", nil)
+		if err != nil {
+			return 0, err
+		}
+		if userAgent != "" {
+			req.Header.Set("User-Agent", userAgent)
+		}
+
+		resp, err := c.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return 0, nil
+		}
+		return crawlDelay(resp.Body, userAgent), nil
+	}
+}
+
+// crawlDelay parses a robots.txt and returns the Crawl-delay applying to the user agent.
+func crawlDelay(robots []byte, userAgent string) time.Duration {
+	userAgent = strings.ToLower(userAgent)
+
+	var (
+		agents            []string // User agents of the current group.
+		inRules           bool     // Whether the rules of the current group have started.
+		specific, generic time.Duration
+		found             bool // Whether a delay was found for the specific user agent.
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(robots))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			// A user agent following rules starts a new group.
+			if inRules {
+				agents, inRules = nil, false
+			}
+			agents = append(agents, strings.ToLower(value))
+
+		case "crawl-delay":
+			inRules = true
+			seconds, err := strconv.ParseFloat(value, 64)
+			if err != nil || seconds < 0 {
+				continue
+			}
+			delay := time.Duration(seconds * float64(time.Second))
+
+			for _, agent := range agents {
+				switch {
+				case agent == "*":
+					generic = delay
+				case userAgent != "" && strings.Contains(userAgent, agent):
+					specific, found = delay, true
+				}
+			}
+
+		default:
+			inRules = true
+		}
+	}
+
+	if found {
+		return specific
+	}
+	return generic
+}
@@ -0,0 +1,10 @@
+module github.com/ricardocastanho/scrapify/fetch/textcharset
+
+go 1.23.0
+
+require (
+	github.com/ricardocastanho/scrapify v0.0.0
+	golang.org/x/text v0.21.0
+)
+
+replace github.com/ricardocastanho/scrapify => ../..
@@ -0,0 +1,36 @@
+// Package textcharset decodes the charsets of the WHATWG Encoding Standard, those browsers support such as Shift_JIS,
+// GBK or EUC-KR, for fetch.WithCharsetDetection with golang.org/x/text:
+//
+//	client := fetch.New(fetch.WithCharsetDetection(), textcharset.WithCharsets())
+//
+// It is a module of its own, so the scrapify module does not depend on golang.org/x/text.
+package textcharset
+
+import (
+	"github.com/ricardocastanho/scrapify/fetch"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// WithCharsets makes WithCharsetDetection decode every charset of the Encoding Standard, on top of those built into fetch.
+func WithCharsets() fetch.Option {
+	return fetch.WithCharsetLookup(Lookup)
+}
+
+// Lookup returns the decoder of the charset with the given label, such as "shift_jis" or "csShiftJIS",
+// or nil if the Encoding Standard does not define it.
+func Lookup(name string) fetch.CharsetDecoder {
+	enc, err := htmlindex.Get(name)
+	if err != nil {
+		return nil
+	}
+	return Decoder(enc)
+}
+
+// Decoder returns a decoder of enc to UTF-8, to register with fetch.WithCharset.
+// Unlike the Bytes method of an encoding.Decoder, it is safe for the concurrent use of a fetch.Client.
+func Decoder(enc encoding.Encoding) fetch.CharsetDecoder {
+	return func(body []byte) ([]byte, error) {
+		return enc.NewDecoder().Bytes(body)
+	}
+}
@@ -0,0 +1,81 @@
+package textcharset_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ricardocastanho/scrapify/fetch"
+	"github.com/ricardocastanho/scrapify/fetch/textcharset"
+	"golang.org/x/text/encoding/japanese"
+)
+
+// serve serves body with the given Content-Type header.
+func serve(t *testing.T, contentType, body string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestWithCharsets(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+	}{
+		{"shift_jis meta", "text/html", `<meta charset="Shift_JIS"><title>` + "\x93\xfa\x96\x7b\x8c\xea</title>", `<meta charset="Shift_JIS"><title>日本語</title>`},
+		{"gbk header", "text/plain; charset=GBK", "\xd6\xd0\xce\xc4", "中文"},
+		{"euc-kr header", "text/plain; charset=EUC-KR", "\xc7\xd1\xb1\xb9\xbe\xee", "한국어"},
+		{"windows-1252 built in", "text/plain; charset=windows-1252", "caf\xe9", "café"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := serve(t, tt.contentType, tt.body)
+			client := fetch.New(fetch.WithCharsetDetection(), textcharset.WithCharsets())
+
+			resp, err := client.Get(context.Background(), srv.URL)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if string(resp.Body) != tt.want {
+				t.Errorf("body %q, want %q", resp.Body, tt.want)
+			}
+			if got := resp.Header.Get("Content-Type"); !strings.HasSuffix(got, "charset=utf-8") {
+				t.Errorf("Content-Type %q, want the utf-8 charset", got)
+			}
+		})
+	}
+}
+
+func TestWithCharsetsLeavesUnknownCharsets(t *testing.T) {
+	srv := serve(t, "text/plain; charset=x-unknown", "caf\xe9")
+
+	resp, err := fetch.New(fetch.WithCharsetDetection(), textcharset.WithCharsets()).Get(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(resp.Body) != "caf\xe9" {
+		t.Errorf("body %q, want it untouched", resp.Body)
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	srv := serve(t, "text/html; charset=Shift_JIS", "\x93\xfa\x96\x7b\x8c\xea")
+	client := fetch.New(fetch.WithCharsetDetection(), fetch.WithCharset("shift_jis", textcharset.Decoder(japanese.ShiftJIS)))
+
+	resp, err := client.Get(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if string(resp.Body) != "日本語" {
+		t.Errorf("body %q, want 日本語", resp.Body)
+	}
+}
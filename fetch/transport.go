@@ -0,0 +1,80 @@
+package fetch
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+// TransportConfig holds the connection settings of a Client.
+// Start from DefaultTransportConfig and override the fields to change, since zero values disable the corresponding setting.
+type TransportConfig struct {
+	MaxIdleConns        int           // Maximum idle connections across all hosts (0 means no limit).
+	MaxIdleConnsPerHost int           // Maximum idle connections kept per host.
+	MaxConnsPerHost     int           // Maximum connections per host, including active ones (0 means no limit).
+	IdleConnTimeout     time.Duration // How long an idle connection is kept before being closed (0 means forever).
+	TLSHandshakeTimeout time.Duration // Maximum time spent on a TLS handshake (0 means no timeout).
+	ForceAttemptHTTP2   bool          // Negotiates HTTP/2 when the server supports it.
+}
+
+// DefaultTransportConfig returns the connection settings used by New.
+// Compared to the standard library defaults, it keeps many more idle connections per host (32 instead of 2),
+// so a crawl hitting the same host concurrently reuses connections instead of constantly opening new ones.
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		MaxIdleConns:        256,
+		MaxIdleConnsPerHost: 32,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+		ForceAttemptHTTP2:   true,
+	}
+}
+
+// WithTransportTuning replaces the connection settings of the Client.
+func WithTransportTuning(cfg TransportConfig) Option {
+	return func(c *Client) {
+		c.transport = cfg
+	}
+}
+
+// DialFunc opens a network connection to addr, given as host:port, like net.Dialer.DialContext.
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// WithDialer makes the Client open its connections with dial instead of the standard library dialer,
+// to resolve hosts through a custom resolver or pin them to specific addresses, such as a staging server:
+//
+//	dialer := &net.Dialer{Timeout: 30 * time.Second}
+//	client := fetch.New(fetch.WithDialer(func(ctx context.Context, network, addr string) (net.Conn, error) {
+//		if addr == "example.com:443" {
+//			addr = "10.0.0.12:443"
+//		}
+//		return dialer.DialContext(ctx, network, addr)
+//	}))
+//
+// TLS is still negotiated with the requested host name, so certificates are verified against it.
+// Pass the DialContext method of a net.Dialer with a custom net.Resolver to only change how host names are resolved.
+func WithDialer(dial DialFunc) Option {
+	return func(c *Client) {
+		c.dial = dial
+	}
+}
+
+// build creates an HTTP transport from the configuration, keeping the standard library defaults for everything else.
+// A non-nil dial replaces the dialer of the transport.
+func (cfg TransportConfig) build(dial DialFunc) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if dial != nil {
+		t.DialContext = dial
+	}
+	t.MaxIdleConns = cfg.MaxIdleConns
+	t.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	t.MaxConnsPerHost = cfg.MaxConnsPerHost
+	t.IdleConnTimeout = cfg.IdleConnTimeout
+	t.TLSHandshakeTimeout = cfg.TLSHandshakeTimeout
+	t.ForceAttemptHTTP2 = cfg.ForceAttemptHTTP2
+
+	// Compressed bodies are decoded by the Client, whatever the request headers are.
+	t.DisableCompression = true
+	return t
+}
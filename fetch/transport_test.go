@@ -0,0 +1,59 @@
+package fetch_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ricardocastanho/scrapify/fetch"
+)
+
+// benchmarkSameHost fetches a page of a single host in bursts of concurrent requests, like a crawl fanning out the item URLs
+// of a listing page, and reports the connections opened per burst.
+func benchmarkSameHost(b *testing.B, client *fetch.Client) {
+	var conns atomic.Int64
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A little latency keeps the requests in flight at once, as against a real host.
+		time.Sleep(time.Millisecond)
+		w.Write([]byte("<html><body>page</body></html>"))
+	}))
+	srv.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	b.ResetTimer()
+	for range b.N {
+		var wg sync.WaitGroup
+		for range 16 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := client.Get(context.Background(), srv.URL); err != nil {
+					b.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+	b.ReportMetric(float64(conns.Load())/float64(b.N), "conns/op")
+}
+
+func BenchmarkSameHostThroughput(b *testing.B) {
+	b.Run("stdlib-defaults", func(b *testing.B) {
+		cfg := fetch.DefaultTransportConfig()
+		cfg.MaxIdleConnsPerHost = http.DefaultMaxIdleConnsPerHost
+		benchmarkSameHost(b, fetch.New(fetch.WithTransportTuning(cfg)))
+	})
+	b.Run("tuned", func(b *testing.B) {
+		benchmarkSameHost(b, fetch.New())
+	})
+}
@@ -0,0 +1,115 @@
+package scrapify
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Edge is a link of the crawl graph: the page From returned the URL To from GetUrls.
+type Edge struct {
+	From string   // URL of the page.
+	To   string   // Discovered URL, in its canonical form.
+	Kind WorkKind // ItemWork for an item URL, PageWork for a next page.
+}
+
+// WithGraphRecorder records the link graph of the crawl, with an Edge from every page to each item URL and next page
+// its GetUrls call returned, to analyze or visualize the structure of a site. Edges are recorded before deduplication,
+// so links to URLs already crawled, or dropped by WithMaxURLsPerPage, WithMaxHosts or the pagination options, are kept.
+// Read the graph with Graph or export it with WriteGraphDOT. Recording is off by default, since the graph grows with every link.
+func WithGraphRecorder() Option {
+	return func(o *options) {
+		o.recordGraph = true
+	}
+}
+
+// linkGraph accumulates the distinct edges of the crawl graph, in discovery order.
+type linkGraph struct {
+	mu    sync.Mutex        // Guards the fields below.
+	edges []Edge            // Recorded edges.
+	seen  map[Edge]struct{} // Recorded edges, for deduplication.
+}
+
+// newLinkGraph creates the graph of WithGraphRecorder, or returns nil when the graph is not recorded.
+func newLinkGraph(o options) *linkGraph {
+	if !o.recordGraph {
+		return nil
+	}
+	return &linkGraph{seen: make(map[Edge]struct{})}
+}
+
+// add records the edge, unless it was already recorded.
+func (g *linkGraph) add(e Edge) {
+	if g == nil {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if _, ok := g.seen[e]; ok {
+		return
+	}
+	g.seen[e] = struct{}{}
+	g.edges = append(g.edges, e)
+}
+
+// link records the edges from the page to the item URLs and next pages its GetUrls call returned.
+func (s *Scraper[T]) link(page string, urls, nextPages []URL) {
+	if s.graph == nil {
+		return
+	}
+
+	for _, url := range urls {
+		s.graph.add(Edge{From: page, To: s.canonical(url.URL), Kind: ItemWork})
+	}
+	for _, url := range nextPages {
+		s.graph.add(Edge{From: page, To: s.canonical(url.URL), Kind: PageWork})
+	}
+}
+
+// Graph returns the edges of the crawl graph recorded so far with WithGraphRecorder, in discovery order, as an edge list.
+// It is safe to call while the crawl is running, and returns nil when the graph is not recorded.
+func (s *Scraper[T]) Graph() []Edge {
+	if s.graph == nil {
+		return nil
+	}
+
+	s.graph.mu.Lock()
+	defer s.graph.mu.Unlock()
+
+	return append([]Edge(nil), s.graph.edges...)
+}
+
+// WriteGraphDOT writes the crawl graph recorded with WithGraphRecorder in the DOT language of Graphviz,
+// with item URLs drawn as boxes and links to next pages dashed.
+func (s *Scraper[T]) WriteGraphDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph crawl {")
+
+	edges := s.Graph()
+	items := make(map[string]bool)
+	for _, e := range edges {
+		if e.Kind == ItemWork && !items[e.To] {
+			items[e.To] = true
+			fmt.Fprintf(bw, "\t%s [shape=box];\n", dotQuote(e.To))
+		}
+	}
+	for _, e := range edges {
+		style := ""
+		if e.Kind == PageWork {
+			style = " [style=dashed]"
+		}
+		fmt.Fprintf(bw, "\t%s -> %s%s;\n", dotQuote(e.From), dotQuote(e.To), style)
+	}
+
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// dotQuote quotes a URL as a DOT identifier.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
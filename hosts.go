@@ -0,0 +1,65 @@
+package scrapify
+
+import "sync"
+
+// WithMaxHosts bounds the crawl to n distinct hosts: once n hosts have been encountered, discovered URLs on any other host
+// are dropped, while URLs on the hosts already seen are crawled normally. The hosts of the start pages are encountered first
+// and always crawled, even if there are more than n of them. It bounds crawls that follow some external links without
+// restricting them to their start hosts. The default of zero is unlimited.
+func WithMaxHosts(n int) Option {
+	return func(o *options) {
+		o.maxHosts = n
+	}
+}
+
+// hostLimit admits URLs on up to a maximum number of distinct hosts.
+type hostLimit struct {
+	mu   sync.Mutex          // Guards seen.
+	max  int                 // Maximum number of hosts.
+	seen map[string]struct{} // Hosts encountered so far.
+}
+
+// newHostLimit creates the hostLimit of WithMaxHosts, with the hosts of the start URLs already encountered.
+// It returns nil when the number of hosts is unlimited.
+func newHostLimit[T any](o options, strategies []ScraperStrategy[T]) *hostLimit {
+	if o.maxHosts <= 0 {
+		return nil
+	}
+
+	h := &hostLimit{max: o.maxHosts, seen: make(map[string]struct{})}
+	for _, strategy := range strategies {
+		h.add(hostKey(strategy.Url))
+	}
+	return h
+}
+
+// allow reports whether URLs on the host are admitted, encountering it if the limit is not reached yet.
+func (h *hostLimit) allow(host string) bool {
+	if h == nil {
+		return true
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.seen[host]; ok {
+		return true
+	}
+	if len(h.seen) >= h.max {
+		return false
+	}
+	h.seen[host] = struct{}{}
+	return true
+}
+
+// add encounters the host even beyond the limit, for hosts that were admitted before, such as those of a resumed frontier.
+func (h *hostLimit) add(host string) {
+	if h == nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seen[host] = struct{}{}
+}
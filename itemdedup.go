@@ -0,0 +1,54 @@
+package scrapify
+
+import "sync"
+
+// WithItemDedup drops the items whose key, as returned by keyFn, was already delivered during the crawl, for items reachable
+// under several URLs that URL deduplication cannot catch, such as a product listed in several categories with a different URL.
+// Items are deduplicated after WithTransform, so keyFn sees the transformed items. Dropped items reach neither the callback
+// nor the handlers, and are counted in Stats.Duplicates. The keys of all the delivered items are kept in memory for the whole crawl.
+// T must be the type of data of the Scraper, otherwise Run and Probe fail without crawling.
+func WithItemDedup[T any](keyFn func(item T) string) Option {
+	return func(o *options) {
+		o.itemKey = keyFn
+	}
+}
+
+// itemKeys is the concurrency-safe set of the keys of the delivered items, for WithItemDedup.
+type itemKeys[T any] struct {
+	key  func(T) string      // Function registered with WithItemDedup.
+	mu   sync.Mutex          // Guards seen.
+	seen map[string]struct{} // Keys of the delivered items.
+}
+
+// newItemKeys returns the set of item keys of WithItemDedup, checking its function matches the type of data of the Scraper.
+// It returns nil when items are not deduplicated, and adds a mismatch to errs.
+func newItemKeys[T any](o options, errs *[]error) *itemKeys[T] {
+	if o.itemKey == nil {
+		return nil
+	}
+
+	fn, ok := o.itemKey.(func(T) string)
+	if !ok {
+		*errs = append(*errs, mismatch[T]("WithItemDedup function", o.itemKey))
+		return nil
+	}
+	return &itemKeys[T]{key: fn, seen: make(map[string]struct{})}
+}
+
+// add records the key of the item and reports whether it is new, in which case the item is delivered.
+func (k *itemKeys[T]) add(item T) bool {
+	if k == nil {
+		return true
+	}
+
+	key := k.key(item)
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if _, ok := k.seen[key]; ok {
+		return false
+	}
+	k.seen[key] = struct{}{}
+	return true
+}
@@ -0,0 +1,105 @@
+package scrapify
+
+import (
+	"context"
+	"sync"
+)
+
+// WithOrderedItems processes the item URLs of each page one at a time, in the order GetUrls returned them: the GetData call
+// of an item URL only starts once the previous item URL of its page is done, so the items of a page are scraped and delivered
+// in a reproducible order, for tests and debugging. Combined with WithConcurrency(1), the output of the whole crawl is
+// reproducible run to run on the same site.
+// It costs throughput: the item URLs of a page never run in parallel, so parallelism only comes from the pages processed
+// at once, and a crawl whose items all come from one page runs fully sequentially. The next pages of a page are scheduled
+// before its first item URL. A page stays in the checkpoint frontier until its last item URL is done, so resuming a crawl
+// discovers the rest of its item URLs again. It has no effect with a Frontier, whose work may be popped by other processes.
+func WithOrderedItems() Option {
+	return func(o *options) {
+		o.orderedItems = true
+	}
+}
+
+// itemChain is the remaining item URLs of a page processed in order, with WithOrderedItems.
+type itemChain struct {
+	page  Work   // The page that discovered the item URLs.
+	items []Work // Item URLs not scheduled yet.
+}
+
+// itemChains keeps the chain of the item URL being processed for each ordered page, by its visit key.
+type itemChains struct {
+	mu     sync.Mutex            // Guards chains.
+	chains map[string]*itemChain // Chains by the visit key of their scheduled item URL.
+}
+
+// orderItems reports whether the item URLs of pages are processed in order, see WithOrderedItems.
+func (s *Scraper[T]) orderItems() bool {
+	_, shared := s.scheduler.(Frontier)
+	return s.orderedItems && !shared
+}
+
+// chain schedules the first admitted item URL of a page, the others being scheduled one by one as the previous ones finish.
+// It reports whether one was scheduled, in which case the page stays tracked until its last item URL is done.
+func (s *Scraper[T]) chain(ctx context.Context, page Work, items []Work) (bool, error) {
+	return s.advance(ctx, &itemChain{page: page, items: items})
+}
+
+// advance schedules the next admitted item URL of the chain and reports whether one was scheduled.
+func (s *Scraper[T]) advance(ctx context.Context, c *itemChain) (bool, error) {
+	for len(c.items) > 0 {
+		w := c.items[0]
+		c.items = c.items[1:]
+
+		// Register the chain before scheduling the item URL, which may be done before admit returns.
+		// An item URL already registered by another chain is pending already, and is skipped.
+		w.URL = s.canonical(w.URL)
+		key := s.visitKey(w)
+		s.chains.mu.Lock()
+		if _, ok := s.chains.chains[key]; ok {
+			s.chains.mu.Unlock()
+			continue
+		}
+		s.chains.chains[key] = c
+		s.chains.mu.Unlock()
+
+		ok, err := s.admit(ctx, w)
+		if ok {
+			return true, nil
+		}
+		s.chains.mu.Lock()
+		delete(s.chains.chains, key)
+		s.chains.mu.Unlock()
+		if err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// proceed schedules the item URL following the one of the work in its chain, if any, once the work is done.
+// The page of the chain is untracked once its last item URL is done, and stays in the frontier when the crawl is cancelled.
+func (s *Scraper[T]) proceed(ctx context.Context, w Work) {
+	if s.chains.chains == nil {
+		return
+	}
+
+	key := s.visitKey(w)
+	s.chains.mu.Lock()
+	c := s.chains.chains[key]
+	delete(s.chains.chains, key)
+	s.chains.mu.Unlock()
+	if c == nil || ctx.Err() != nil {
+		return
+	}
+
+	if ok, err := s.advance(ctx, c); !ok && err == nil {
+		s.untrack(c.page)
+	}
+}
+
+// newItemChains creates the chains of WithOrderedItems, without any map when item URLs are not ordered.
+func newItemChains(o options) itemChains {
+	if !o.orderedItems {
+		return itemChains{}
+	}
+	return itemChains{chains: make(map[string]*itemChain)}
+}
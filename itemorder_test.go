@@ -0,0 +1,67 @@
+package scrapify_test
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+func TestOrderedItemsFollowGetUrlsOrder(t *testing.T) {
+	const items = 20
+	want, _, _ := listing(items)(context.Background(), "https://example.com/list")
+
+	for run := range 5 {
+		var got collector[string]
+		scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+			Scraper: funcScraper[string]{urls: listing(items), data: func(ctx context.Context, ch chan<- string, url string) error {
+				// Random latencies would reorder the items if they ran concurrently.
+				time.Sleep(time.Duration(rand.N(500)) * time.Microsecond)
+				return echo(ctx, ch, url)
+			}},
+			Url: "https://example.com/list",
+		}}, got.add, 0, scrapify.WithOrderedItems(), scrapify.WithConcurrency(1))
+
+		if err := scraper.Run(context.Background()); err != nil {
+			t.Fatalf("run %d: Run: %v", run, err)
+		}
+		if !slices.Equal(got.result(), want) {
+			t.Fatalf("run %d: items = %v, want %v", run, got.result(), want)
+		}
+	}
+}
+
+func TestOrderedItemsRunOneAtATime(t *testing.T) {
+	var running, peak int
+	var mu sync.Mutex
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+		Scraper: funcScraper[string]{urls: listing(10), data: func(ctx context.Context, ch chan<- string, url string) error {
+			mu.Lock()
+			running++
+			peak = max(peak, running)
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			running--
+			mu.Unlock()
+			return nil
+		}},
+		Url: "https://example.com/list",
+	}}, nil, 0, scrapify.WithOrderedItems())
+
+	if err := scraper.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if peak != 1 {
+		t.Errorf("%d item URLs of the page ran at once, want 1", peak)
+	}
+	if got := scraper.Stats().URLs; got != 10 {
+		t.Errorf("Stats().URLs = %d, want 10", got)
+	}
+}
@@ -0,0 +1,83 @@
+package scrapify
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// latencyAlpha is the weight of the latest observation in the moving average of a host's latency.
+const latencyAlpha = 0.3
+
+// WithLatencyAwareDelay spaces out the requests to each host by multiplier times the host's observed latency, bounded by minDelay and maxDelay,
+// automatically backing off from hosts that slow down under load. The latency of a host is an exponentially weighted moving average
+// of the time its GetUrls and GetData calls take to get a response, as recorded with RecordResponse, or to return when none is recorded.
+// Until a host has been observed, its requests are spaced by minDelay. Both GetUrls and GetData calls count as requests.
+func WithLatencyAwareDelay(multiplier float64, minDelay, maxDelay time.Duration) Option {
+	return func(o *options) {
+		o.latencyMultiplier = multiplier
+		o.latencyMin = minDelay
+		o.latencyMax = maxDelay
+	}
+}
+
+// latencyDelays paces the requests of each host by a multiple of its observed latency.
+type latencyDelays struct {
+	multiplier float64                  // Delay in multiples of the latency.
+	min, max   time.Duration            // Bounds of the delay.
+	limiter    *rateLimiter             // Reserves the slots of each host, with its own interval.
+	mu         sync.Mutex               // Guards ewma.
+	ewma       map[string]time.Duration // Moving average of the latency of each host.
+}
+
+// newLatencyDelays creates the latency-aware delays, or returns nil when they are not configured.
+func newLatencyDelays(o options) *latencyDelays {
+	if o.latencyMultiplier <= 0 {
+		return nil
+	}
+
+	return &latencyDelays{
+		multiplier: o.latencyMultiplier,
+		min:        o.latencyMin,
+		max:        o.latencyMax,
+		limiter:    &rateLimiter{clock: o.clock, next: make(map[string]time.Time)},
+		ewma:       make(map[string]time.Duration),
+	}
+}
+
+// wait blocks until a request to the host respects its delay, or until the context is done.
+func (l *latencyDelays) wait(ctx context.Context, host string) error {
+	l.mu.Lock()
+	delay := l.min
+	if latency, ok := l.ewma[host]; ok {
+		delay = time.Duration(float64(latency) * l.multiplier)
+	}
+	l.mu.Unlock()
+
+	delay = max(delay, l.min)
+	if l.max > 0 {
+		delay = min(delay, l.max)
+	}
+	if delay <= 0 {
+		return nil
+	}
+	return l.limiter.waitInterval(ctx, host, delay)
+}
+
+// observe adds a latency measurement of the host to its moving average.
+func (l *latencyDelays) observe(host string, latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if prev, ok := l.ewma[host]; ok {
+		latency = time.Duration(latencyAlpha*float64(latency) + (1-latencyAlpha)*float64(prev))
+	}
+	l.ewma[host] = latency
+}
+
+// observe records the latency of a call on the URL, when latency-aware delays are enabled.
+func (s *Scraper[T]) observe(url string, rec *recorder) {
+	if s.latency != nil {
+		s.latency.observe(hostKey(url), rec.latency())
+	}
+}
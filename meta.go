@@ -0,0 +1,168 @@
+package scrapify
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ItemMeta describes the provenance of a scraped item, for data lineage and debugging.
+type ItemMeta struct {
+	URL           string     // Item URL whose GetData call produced the item.
+	Strategy      int        // Index of the strategy of the URL in the list given to NewScraper.
+	StrategyID    string     // ID of the strategy of the URL.
+	Seed          string     // Start URL of the strategy.
+	Depth         int        // Depth of the URL, see Work.Depth.
+	FetchedAt     time.Time  // When the response was recorded, or when the GetData call started if none was.
+	StatusCode    int        // Status code of the recorded response (0 if none was).
+	FinalURL      string     // URL of the recorded response, after redirects (empty if none was).
+	Redirects     []string   // URLs redirected before reaching FinalURL, starting with the requested one.
+	RedirectChain []Redirect // Redirect responses followed before reaching FinalURL, starting with the requested URL's.
+}
+
+// Redirect is a redirect response followed while fetching a URL, a hop of its redirect chain.
+type Redirect struct {
+	URL        string // URL of the request answered with the redirect.
+	StatusCode int    // Status code of the redirect, such as 301 or 302.
+	Location   string // URL redirected to, resolved against URL.
+}
+
+// ResponseInfo describes the response fetched for a GetUrls or GetData call, as reported with RecordResponse.
+type ResponseInfo struct {
+	URL           string     // URL of the response, after redirects.
+	StatusCode    int        // HTTP status code.
+	Redirects     []string   // URLs redirected before reaching URL, starting with the requested one.
+	RedirectChain []Redirect // Redirect responses followed before reaching URL, oldest first (nil without redirects).
+	Bytes         int64      // Size of the response body.
+	Canonical     string     // Canonical URL of the content, such as the one of a <link rel="canonical"> tag (empty if unknown).
+}
+
+// RecordResponse reports the response fetched with the context of a GetUrls or GetData call, so it appears in the ItemMeta
+// of the items of that call. The fetch client calls it for every response; scrapers using another HTTP client can call it themselves.
+// When several responses are recorded in the same call, the last one wins. Every response is also counted in Stats.StatusCodes and Stats.Bytes.
+// A Canonical URL differing from the URL of the call is marked as visited, so it is not crawled again, and the items of
+// a GetData call whose canonical URL was already visited are dropped as duplicates and counted in Stats.Duplicates.
+// Outside of a call, it does nothing.
+func RecordResponse(ctx context.Context, info ResponseInfo) {
+	if r, ok := ctx.Value(recorderKey{}).(*recorder); ok {
+		r.record(info)
+	}
+}
+
+// recorderKey is the context key under which the recorder of the current call is stored.
+type recorderKey struct{}
+
+// recorder keeps the last response recorded during a call.
+type recorder struct {
+	clock     Clock              // Source of time.
+	counters  []*counters        // Counters of the crawl and of the strategy, where status codes and bytes are counted.
+	onRecord  func(ResponseInfo) // Invoked with every recorded response (may be nil).
+	mu        sync.Mutex         // Guards the fields below.
+	info      ResponseInfo       // Last recorded response.
+	fetchedAt time.Time          // When the response was recorded, or when the call started.
+	started   time.Time          // When the call started.
+	recorded  bool               // Whether a response was recorded.
+	items     int                // Number of items sent by the call.
+	dup       bool               // Whether the call duplicates an item URL already visited under its canonical URL.
+}
+
+// newRecorder creates a recorder for a call starting now, counting status codes in the given counters.
+func newRecorder(clock Clock, counters ...*counters) *recorder {
+	now := clock.Now()
+	return &recorder{clock: clock, counters: counters, fetchedAt: now, started: now}
+}
+
+// record stores the response and counts its status code and bytes.
+func (r *recorder) record(info ResponseInfo) {
+	for _, c := range r.counters {
+		c.status(info.StatusCode)
+		c.bytes.Add(info.Bytes)
+	}
+	if r.onRecord != nil {
+		r.onRecord(info)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.info = info
+	r.fetchedAt = r.clock.Now()
+	r.recorded = true
+}
+
+// count counts an item sent by the call.
+func (r *recorder) count() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.items++
+}
+
+// duplicate marks the call as a duplicate, whose items are dropped.
+func (r *recorder) duplicate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.dup = true
+}
+
+// duplicated reports whether the call was marked as a duplicate.
+func (r *recorder) duplicated() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.dup
+}
+
+// latency returns how long the call took to get its response, or has been running if none was recorded.
+func (r *recorder) latency() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.recorded {
+		return r.fetchedAt.Sub(r.started)
+	}
+	return r.clock.Now().Sub(r.started)
+}
+
+// OnItemMeta registers a handler invoked for every scraped item, after the callback and the OnItem handler,
+// with the provenance of the item.
+// T must be the type of data of the Scraper, otherwise Run and Probe fail without crawling.
+func OnItemMeta[T any](fn func(item T, meta ItemMeta)) Option {
+	return func(o *options) {
+		o.onItemMeta = fn
+	}
+}
+
+// metaHandler returns the handler registered with OnItemMeta, checking it matches the type of data of the Scraper.
+// A mismatch is added to errs and disables the option.
+func metaHandler[T any](o options, errs *[]error) func(T, ItemMeta) {
+	if o.onItemMeta == nil {
+		return nil
+	}
+
+	fn, ok := o.onItemMeta.(func(T, ItemMeta))
+	if !ok {
+		*errs = append(*errs, mismatch[T]("OnItemMeta handler", o.onItemMeta))
+	}
+	return fn
+}
+
+// meta returns the provenance of an item produced by the work, with the response recorded so far.
+func (s *Scraper[T]) meta(w Work, r *recorder) ItemMeta {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return ItemMeta{
+		URL:           w.URL,
+		Strategy:      w.Strategy,
+		StrategyID:    s.strategy[w.Strategy].ID,
+		Seed:          s.strategy[w.Strategy].Url,
+		Depth:         w.Depth,
+		FetchedAt:     r.fetchedAt,
+		StatusCode:    r.info.StatusCode,
+		FinalURL:      r.info.URL,
+		Redirects:     r.info.Redirects,
+		RedirectChain: r.info.RedirectChain,
+	}
+}
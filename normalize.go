@@ -0,0 +1,74 @@
+package scrapify
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// DefaultIndexFiles are the index file names merged into their directory by a URLNormalizer unless it sets its own.
+var DefaultIndexFiles = []string{"index.html", "index.htm", "index.php", "default.html", "default.htm", "default.aspx"}
+
+// URLNormalizer rewrites URLs into a canonical form, so that different spellings of the same resource are fetched only once.
+// Its zero value lowercases the host, drops default ports, strips fragments and merges index files into their directory.
+type URLNormalizer struct {
+	KeepFragments      bool     // Keeps #fragments, for sites routing on them. They never change the fetched resource otherwise.
+	DistinctIndexFiles bool     // Treats /path/index.html and /path/ as distinct URLs instead of merging them into /path/.
+	IndexFiles         []string // Index file names merged into their directory (defaults to DefaultIndexFiles).
+}
+
+// Normalize returns the canonical form of the URL. URLs that cannot be parsed are returned unchanged.
+func (n URLNormalizer) Normalize(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+
+	u.Host = strings.ToLower(u.Host)
+	if port := u.Port(); (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		u.Host = strings.TrimSuffix(u.Host, ":"+port)
+	}
+	if u.Host != "" && u.Path == "" {
+		u.Path = "/"
+	}
+
+	if !n.KeepFragments {
+		u.Fragment, u.RawFragment = "", ""
+	}
+
+	if !n.DistinctIndexFiles {
+		files := n.IndexFiles
+		if files == nil {
+			files = DefaultIndexFiles
+		}
+
+		dir, file := path.Split(u.Path)
+		for _, index := range files {
+			if strings.EqualFold(file, index) {
+				u.Path = dir
+				if u.RawPath != "" {
+					u.RawPath, _ = path.Split(u.RawPath)
+				}
+				break
+			}
+		}
+	}
+	return u.String()
+}
+
+// WithURLNormalizer normalizes every URL with the given URLNormalizer before it is deduplicated and scheduled,
+// so the scrapers receive the canonical URLs. The start URLs of the strategies are normalized as well.
+// By default URLs are used exactly as returned by GetUrls.
+func WithURLNormalizer(n URLNormalizer) Option {
+	return func(o *options) {
+		o.normalize = n.Normalize
+	}
+}
+
+// canonical returns the URL normalized with the configured URLNormalizer, if any, without the WithIgnoreQueryParams parameters.
+func (o *options) canonical(url string) string {
+	if o.normalize != nil {
+		url = o.normalize(url)
+	}
+	return stripQueryParams(url, o.ignoredParams)
+}
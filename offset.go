@@ -0,0 +1,148 @@
+package scrapify
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"sync"
+)
+
+// OffsetFunc fetches a batch of an offset-paginated API, given its URL with the offset and limit parameters set,
+// and returns its items and the total number of items of the API, or a negative total when the API does not report it.
+type OffsetFunc[T any] func(ctx context.Context, url string) (items []T, total int, err error)
+
+// OffsetPaginationScraper is an IScraper for APIs paginated with offset and limit query parameters,
+// such as https://api.example.com/products?offset=100&limit=50. Use the URL of the API, without the parameters,
+// as the Url of the strategy: the scraper generates the URLs of the batches and stops at the last one.
+//
+// When the first batch reports the total number of items, the URLs of all the batches are returned at once as item URLs,
+// so they are fetched concurrently, the last one being a partial batch when the total is not a multiple of the limit.
+// Otherwise batches are fetched in sequence, one page per batch, until a batch is empty or shorter than the limit.
+// A batch fetched to discover the next ones is not fetched again to scrape its items.
+type OffsetPaginationScraper[T any] struct {
+	Fetch       OffsetFunc[T] // Fetches a batch.
+	Limit       int           // Number of items per batch.
+	OffsetParam string        // Name of the offset parameter ("offset" if empty).
+	LimitParam  string        // Name of the limit parameter ("limit" if empty).
+
+	batches sync.Map // Items of the batches fetched by GetUrls, by item URL, until GetData sends them.
+}
+
+// NewOffsetPaginationScraper creates an OffsetPaginationScraper fetching batches of limit items with fetch.
+func NewOffsetPaginationScraper[T any](limit int, fetch OffsetFunc[T]) *OffsetPaginationScraper[T] {
+	return &OffsetPaginationScraper[T]{Fetch: fetch, Limit: limit}
+}
+
+// GetUrls fetches the batch of the page and returns the URLs of the batches to scrape and, when the total is unknown,
+// the page of the next batch. A page is the API URL with only the offset parameter, or without it for the first batch,
+// and the item URL of a batch has both the offset and the limit parameters.
+func (o *OffsetPaginationScraper[T]) GetUrls(ctx context.Context, rawURL string) ([]string, []string, error) {
+	base, offset, err := o.parse(rawURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	batch := o.batchURL(base, offset)
+	items, total, err := o.Fetch(ctx, batch)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(items) == 0 {
+		return nil, nil, nil
+	}
+	o.batches.Store(batch, items)
+
+	if total >= 0 {
+		// Only the first batch fans out, so batches are not discovered twice from a later page.
+		urls := []string{batch}
+		if offset == 0 {
+			for next := o.Limit; next < total; next += o.Limit {
+				urls = append(urls, o.batchURL(base, next))
+			}
+		}
+		return urls, nil, nil
+	}
+
+	if len(items) < o.Limit {
+		return []string{batch}, nil, nil
+	}
+	return []string{batch}, []string{o.pageURL(base, offset+o.Limit)}, nil
+}
+
+// GetData sends the items of the batch, fetching it unless GetUrls already did.
+func (o *OffsetPaginationScraper[T]) GetData(ctx context.Context, ch chan<- T, data *T, rawURL string) error {
+	var items []T
+	if cached, ok := o.batches.LoadAndDelete(rawURL); ok {
+		items = cached.([]T)
+	} else {
+		var err error
+		if items, _, err = o.Fetch(ctx, rawURL); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range items {
+		ch <- item
+	}
+	return nil
+}
+
+// parse returns the URL of the API without the offset and limit parameters, and the offset of the URL (0 if unset).
+func (o *OffsetPaginationScraper[T]) parse(rawURL string) (*url.URL, int, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	q := u.Query()
+	offset := 0
+	if v := q.Get(o.offsetParam()); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil {
+			return nil, 0, err
+		}
+	}
+	q.Del(o.offsetParam())
+	q.Del(o.limitParam())
+	u.RawQuery = q.Encode()
+	return u, offset, nil
+}
+
+// pageURL returns the page of the batch at the offset.
+func (o *OffsetPaginationScraper[T]) pageURL(base *url.URL, offset int) string {
+	return o.withParams(base, map[string]int{o.offsetParam(): offset})
+}
+
+// batchURL returns the item URL of the batch at the offset.
+func (o *OffsetPaginationScraper[T]) batchURL(base *url.URL, offset int) string {
+	return o.withParams(base, map[string]int{o.offsetParam(): offset, o.limitParam(): o.Limit})
+}
+
+// withParams returns the URL with the given integer query parameters set.
+func (o *OffsetPaginationScraper[T]) withParams(base *url.URL, params map[string]int) string {
+	u := *base
+	q := u.Query()
+	for name, value := range params {
+		q.Set(name, strconv.Itoa(value))
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// offsetParam returns the name of the offset parameter.
+func (o *OffsetPaginationScraper[T]) offsetParam() string {
+	if o.OffsetParam == "" {
+		return "offset"
+	}
+	return o.OffsetParam
+}
+
+// limitParam returns the name of the limit parameter.
+func (o *OffsetPaginationScraper[T]) limitParam() string {
+	if o.LimitParam == "" {
+		return "limit"
+	}
+	return o.LimitParam
+}
+
+// Ensure OffsetPaginationScraper satisfies the IScraper interface.
+var _ IScraper[any] = (*OffsetPaginationScraper[any])(nil)
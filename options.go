@@ -0,0 +1,183 @@
+package scrapify
+
+import (
+	"fmt"
+	"time"
+)
+
+// options holds the optional configuration of a Scraper.
+// It is populated by the Option functions passed to NewScraper.
+type options struct {
+	visited            VisitedStore            // Store used to deduplicate URLs (defaults to a MemoryVisitedStore).
+	checkpointPath     string                  // File where the crawl state is checkpointed (empty disables checkpointing).
+	checkpointInterval time.Duration           // Interval between periodic checkpoints.
+	rateLimit          float64                 // Maximum requests per second in each rate-limit bucket (0 means unlimited).
+	depthRateLimits    map[int]float64         // Maximum requests per second in each bucket at some depths, see WithDepthRateLimit.
+	bucketKey          func(url string) string // Assigns URLs to rate-limit buckets (defaults to the URL host).
+	sharedLimiter      *LimiterRegistry        // Rate limiters shared with other scrapers (nil when the scraper has its own).
+	discoveryRate      float64                 // Maximum discovered URLs admitted per second (0 means unlimited).
+	firstErrorStops    bool                    // Cancels the crawl on the first scraper error.
+	scheduler          Scheduler               // Decides the order in which work is executed (defaults to a FIFOScheduler).
+	urlScorer          URLScorer               // Scores the work of the ScoredScheduler set by WithURLScorer (nil without one).
+	startupStagger     time.Duration           // Maximum random delay between the launch of two strategies.
+	clock              Clock                   // Source of time for delays and timers (defaults to the system clock).
+	maxRetries         int                     // Number of retries of a failed call (0 disables retries).
+	retryBase          time.Duration           // Backoff before the first retry.
+	retryMax           time.Duration           // Maximum backoff between retries.
+	retryDeadline      time.Duration           // Maximum time spent retrying a URL (0 means no limit).
+	jitter             JitterStrategy          // Randomizes the backoff (defaults to FullJitter).
+	requestTimeout     time.Duration           // Timeout of each GetUrls and GetData call (0 means no timeout).
+	onComplete         func(Stats)             // Invoked once when the crawl ends.
+	onItem             any                     // Handler registered with OnItem, a func(T, *CrawlHandle).
+	onEmpty            func(url string)        // Invoked for pages without item URLs nor next pages.
+	concurrency        int                     // Maximum number of pages and item URLs processed at once (0 means unlimited).
+	prewarmConns       bool                    // Whether connections to the hosts of the start pages are opened before crawling.
+	orderedItems       bool                    // Whether the item URLs of a page are processed one at a time, in order.
+	adaptiveMin        int                     // Lower bound of the adaptive worker limit.
+	adaptiveMax        int                     // Upper bound of the adaptive worker limit (0 disables adaptive concurrency).
+	normalize          func(url string) string // Rewrites URLs into their canonical form (nil keeps them as is).
+	ignoredParams      []string                // Query parameters stripped from URLs, a trailing * matching any suffix.
+	maxQueryParams     int                     // Maximum query parameters of discovered URLs (0 means unlimited).
+	transform          any                     // Function registered with WithTransform, a func(T) (T, bool).
+	crashFlush         any                     // Function registered with WithCrashFlush, a func([]T).
+	itemKey            any                     // Function registered with WithItemDedup, a func(T) string.
+	hostQuotas         map[string]quota        // Sliding-window quotas keyed by host.
+	defaultQuota       quota                   // Quota of the hosts without their own (a zero max means none).
+	maxStrategies      int                     // Maximum number of strategies active at once (0 means unlimited).
+	dedupScope         DedupScope              // Which strategies share the visited set (defaults to GlobalDedup).
+	onError            func(error)             // Invoked with every recorded error.
+	onItemMeta         any                     // Handler registered with OnItemMeta, a func(T, ItemMeta).
+	shouldContinue     any                     // Predicate registered with WithShouldContinue, a func(Stats, T) bool.
+	crawlDelay         CrawlDelayLookup        // Resolves the crawl delay of an origin.
+	onPaginate         paginateFunc            // Rewrites the next pages of a page before they are scheduled.
+	visitedTTL         time.Duration           // Expiry of visited URLs, replacing the visited store with a TTLVisitedStore when positive.
+	latencyMultiplier  float64                 // Delay between the requests to a host in multiples of its latency (0 disables latency-aware delays).
+	latencyMin         time.Duration           // Minimum latency-aware delay.
+	latencyMax         time.Duration           // Maximum latency-aware delay (0 means unbounded).
+	noPagination       bool                    // Ignores the next pages returned by GetUrls.
+	maxURLsPerPage     int                     // Maximum item URLs scheduled per page (0 means unlimited).
+	pprofAddr          string                  // Address of the pprof server (empty disables profiling).
+	maxHosts           int                     // Maximum number of distinct hosts crawled (0 means unlimited).
+	maxErrors          int                     // Number of errors beyond which the crawl is aborted (0 means never).
+	maxErrorRate       float64                 // Fraction of failed calls beyond which the crawl is aborted (0 means never).
+	errorRateSamples   int                     // Minimum number of calls before the error rate is checked.
+	discoveryRetries   int                     // Retries of a failed GetUrls call (negative means maxRetries).
+	dataRetries        int                     // Retries of a failed GetData call (negative means maxRetries).
+	onDiscoveryError   func(error)             // Invoked with every error of a GetUrls call.
+	onDataError        func(error)             // Invoked with every error of a GetData call.
+	callbackRate       float64                 // Maximum items delivered to the callback per second (0 means unlimited).
+	drainTimeout       time.Duration           // Maximum wait for in-flight work once the crawl is stopped (0 means unbounded).
+	recordGraph        bool                    // Whether the link graph of the crawl is recorded.
+	maxBytes           int64                   // Downloaded bytes after which the crawl is stopped (0 means unbounded).
+	maxItems           int64                   // Delivered items after which the crawl is stopped (0 means unbounded).
+	publisher          EventPublisher          // Publishes the items and errors of the crawl (nil publishes nothing).
+	resultBuffer       int                     // Capacity of the channel of scraped items (0 uses the default).
+	orderedPagination  bool                    // Whether items are delivered in the order of the pages of their strategy.
+}
+
+// Option configures optional behavior of a Scraper.
+type Option func(*options)
+
+// defaultOptions returns the configuration used when no Option overrides it.
+func defaultOptions() options {
+	return options{
+		visited:          NewMemoryVisitedStore(),
+		scheduler:        NewFIFOScheduler(),
+		clock:            systemClock{},
+		jitter:           FullJitter,
+		discoveryRetries: -1,
+		dataRetries:      -1,
+	}
+}
+
+// WithVisitedStore sets the store used to keep track of already scraped URLs.
+// Use NewShardedVisitedStore to reduce lock contention on crawls with many concurrent workers.
+func WithVisitedStore(store VisitedStore) Option {
+	return func(o *options) {
+		if store != nil {
+			o.visited = store
+		}
+	}
+}
+
+// WithVisitedTTL makes visited URLs expire after d, so a URL visited more than d ago is scraped again.
+// It uses a TTLVisitedStore, replacing the store set with WithVisitedStore. To expire visits across successive crawls,
+// pass the same NewTTLVisitedStore to each of them with WithVisitedStore instead.
+func WithVisitedTTL(d time.Duration) Option {
+	return func(o *options) {
+		o.visitedTTL = d
+	}
+}
+
+// WithStartupStagger waits a random delay of up to max between the launch of two strategies,
+// spreading the initial burst of requests when many seed URLs target the same host.
+// The default of zero launches every strategy at once.
+func WithStartupStagger(max time.Duration) Option {
+	return func(o *options) {
+		o.startupStagger = max
+	}
+}
+
+// OnEmpty registers a hook invoked for every page for which GetUrls succeeded but returned neither item URLs nor next pages.
+// Such dead ends are also counted in Stats.Empty. They often reveal soft blocks, where a site serves an empty page instead of an error.
+func OnEmpty(fn func(url string)) Option {
+	return func(o *options) {
+		o.onEmpty = fn
+	}
+}
+
+// WithNoPagination ignores the next pages returned by GetUrls, so only the start pages and their item URLs are scraped.
+// It scopes shallow crawls, or tests of a scraper against a single page, without following pagination.
+// Unlike a depth limit, it only drops next pages: every item URL of the start pages is scraped whatever its depth,
+// and pages enqueued through a CrawlHandle are still followed.
+func WithNoPagination() Option {
+	return func(o *options) {
+		o.noPagination = true
+	}
+}
+
+// WithMaxURLsPerPage only schedules the first k item URLs returned by GetUrls for each page, to sample large listings
+// such as the top results of a search. The dropped URLs are counted in Stats.Truncated. The default of zero keeps every URL.
+func WithMaxURLsPerPage(k int) Option {
+	return func(o *options) {
+		o.maxURLsPerPage = k
+	}
+}
+
+// paginateFunc is the type of the hook registered with OnPaginate.
+type paginateFunc func(fromURL string, nextPages []string) []string
+
+// OnPaginate registers a hook invoked with the next pages returned by GetUrls for a page, before they are scheduled.
+// It returns the next pages to follow, and can filter, reorder, rewrite or truncate them: returning the first K pages
+// limits how far pagination goes, and returning nil stops it. Next pages keep the timeout of the URL they had, if any.
+func OnPaginate(fn func(fromURL string, nextPages []string) []string) Option {
+	return func(o *options) {
+		o.onPaginate = fn
+	}
+}
+
+// WithConcurrency limits the number of pages and item URLs processed at the same time to n.
+// The dispatcher waits for a free worker before starting the next work, so the number of goroutines stays bounded
+// no matter how deep the pagination goes or how many URLs a page returns. The default of zero is unlimited.
+func WithConcurrency(n int) Option {
+	return func(o *options) {
+		o.concurrency = n
+	}
+}
+
+// WithResultBuffer buffers up to n scraped items between the scrapers and the callback, so GetData calls keep scraping
+// while a slow callback catches up instead of blocking on every item. It helps when the callback has variable latency,
+// such as a sink flushing batches, at the cost of holding up to n items in memory on top of those batched by the callback.
+// Buffered items are still delivered after cancellation, but dropped when in-flight work is abandoned with WithDrainTimeout.
+// By default, items are handed to the callback unbuffered, or with a buffer of 256 items under WithCallbackRateLimit.
+func WithResultBuffer(n int) Option {
+	return func(o *options) {
+		o.resultBuffer = n
+	}
+}
+
+// mismatch returns the error of an option whose function, of type fn, does not match the type of data of Scraper[T].
+func mismatch[T any](option string, fn any) error {
+	var zero T
+	return fmt.Errorf("scrapify: %s of type %T does not match Scraper[%T]", option, fn, zero)
+}
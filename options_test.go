@@ -0,0 +1,72 @@
+package scrapify_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+func TestMismatchedOptionsFailRun(t *testing.T) {
+	for name, opt := range map[string]scrapify.Option{
+		"OnItem":             scrapify.OnItem(func(int, *scrapify.CrawlHandle) {}),
+		"WithTransform":      scrapify.WithTransform(func(i int) (int, bool) { return i, true }),
+		"OnItemMeta":         scrapify.OnItemMeta(func(int, scrapify.ItemMeta) {}),
+		"WithShouldContinue": scrapify.WithShouldContinue(func(scrapify.Stats, int) bool { return true }),
+		"WithItemDedup":      scrapify.WithItemDedup(func(i int) string { return "" }),
+		"WithCrashFlush":     scrapify.WithCrashFlush(func([]int) {}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			discovered := false
+			scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+				Scraper: funcScraper[string]{urls: func(ctx context.Context, url string) ([]string, []string, error) {
+					discovered = true
+					return nil, nil, nil
+				}},
+				Url: "https://example.com/list",
+			}}, nil, 0, opt)
+
+			err := scraper.Run(context.Background())
+			if err == nil || !strings.Contains(err.Error(), name) || !strings.Contains(err.Error(), "Scraper[string]") {
+				t.Errorf("Run = %v, want an error naming %s and Scraper[string]", err, name)
+			}
+			if discovered {
+				t.Error("Run crawled despite the mismatched option")
+			}
+		})
+	}
+}
+
+// benchmarkSlowCallback crawls a page of item URLs whose GetData calls take as long as the callback on average,
+// with a callback stalling on every tenth item, like a sink flushing a batch.
+func benchmarkSlowCallback(b *testing.B, opts ...scrapify.Option) {
+	strategies := []scrapify.ScraperStrategy[string]{{
+		Scraper: funcScraper[string]{urls: listing(50), data: func(ctx context.Context, ch chan<- string, url string) error {
+			time.Sleep(200 * time.Microsecond)
+			return echo(ctx, ch, url)
+		}},
+		Url: "https://example.com/list",
+	}}
+	for range b.N {
+		n := 0
+		scraper := scrapify.NewScraper(strategies, func(string) {
+			if n++; n%10 == 0 {
+				time.Sleep(2 * time.Millisecond)
+			}
+		}, 0, append(opts, scrapify.WithConcurrency(4))...)
+		if err := scraper.Run(context.Background()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkResultBuffer(b *testing.B) {
+	b.Run("unbuffered", func(b *testing.B) {
+		benchmarkSlowCallback(b)
+	})
+	b.Run("buffered", func(b *testing.B) {
+		benchmarkSlowCallback(b, scrapify.WithResultBuffer(64))
+	})
+}
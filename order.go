@@ -0,0 +1,182 @@
+package scrapify
+
+import (
+	"maps"
+	"slices"
+	"sync"
+)
+
+// WithOrderedPagination delivers the items of each strategy in the order of its pages: all the items scraped from the item URLs
+// of a page reach the callback before those of the next page, for feeds whose downstream expects them in order, such as newest first.
+// Items of a page are held back until every earlier page of the strategy is done, that is, until its GetUrls call and the GetData
+// calls of its item URLs have returned and their items have been delivered, which delays items and keeps them in memory meanwhile.
+// Pages are ordered as they are scheduled. Items of a page are not ordered among themselves, and work scheduled through a
+// CrawlHandle or resumed from a checkpoint is delivered unordered. When the crawl ends, the items still held are delivered in order.
+func WithOrderedPagination() Option {
+	return func(o *options) {
+		o.orderedPagination = true
+	}
+}
+
+// pageKey identifies a page by its strategy and its sequence number in the pagination of the strategy.
+type pageKey struct {
+	strategy int // Index of the strategy.
+	seq      int // Sequence number of the page, from 1.
+}
+
+// pageOrder tracks the pages of each strategy until they are done, for WithOrderedPagination.
+type pageOrder struct {
+	mu      sync.Mutex      // Guards the fields below.
+	next    []int           // Sequence number of the next page of each strategy.
+	pending map[pageKey]int // Unfinished work and undelivered items of each page (absent once done).
+	wake    chan struct{}   // Signaled when a page is done.
+}
+
+// newPageOrder creates the page order of WithOrderedPagination, or returns nil when pagination is not ordered.
+func newPageOrder(o options, strategies int) *pageOrder {
+	if !o.orderedPagination {
+		return nil
+	}
+
+	next := make([]int, strategies)
+	for i := range next {
+		next[i] = 1
+	}
+	return &pageOrder{next: next, pending: make(map[pageKey]int), wake: make(chan struct{}, 1)}
+}
+
+// page numbers a new page of the strategy, pending until it is done. It returns 0 when pagination is not ordered.
+func (p *pageOrder) page(strategy int) int {
+	if p == nil {
+		return 0
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	seq := p.next[strategy]
+	p.next[strategy]++
+	p.pending[pageKey{strategy, seq}] = 1
+	return seq
+}
+
+// add counts work or an item of the page as pending.
+func (p *pageOrder) add(strategy, seq int) {
+	if p == nil || seq == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.pending[pageKey{strategy, seq}]++
+}
+
+// done counts work or an item of the page as done, waking up the consumer once the whole page is done.
+func (p *pageOrder) done(strategy, seq int) {
+	if p == nil || seq == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := pageKey{strategy, seq}
+	if p.pending[key]--; p.pending[key] > 0 {
+		return
+	}
+	delete(p.pending, key)
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// complete reports whether the page was numbered and is done.
+func (p *pageOrder) complete(strategy, seq int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, pending := p.pending[pageKey{strategy, seq}]
+	return seq < p.next[strategy] && !pending
+}
+
+// woken returns the channel signaled when a page is done, or nil when pagination is not ordered.
+func (p *pageOrder) woken() <-chan struct{} {
+	if p == nil {
+		return nil
+	}
+	return p.wake
+}
+
+// reorderBuffer holds the items of the pages that cannot be delivered yet, for WithOrderedPagination.
+// It is only used by the consumer.
+type reorderBuffer[T any] struct {
+	head []int                     // Sequence number of the page being delivered, for each strategy.
+	held map[pageKey][]delivery[T] // Items waiting for the earlier pages of their strategy.
+}
+
+// newReorderBuffer creates an empty reorder buffer for the strategies.
+func newReorderBuffer[T any](strategies int) *reorderBuffer[T] {
+	head := make([]int, strategies)
+	for i := range head {
+		head[i] = 1
+	}
+	return &reorderBuffer[T]{head: head, held: make(map[pageKey][]delivery[T])}
+}
+
+// order delivers the item in the order of the pages of its strategy, holding it until the earlier pages are done.
+func (s *Scraper[T]) order(buf *reorderBuffer[T], d delivery[T]) {
+	if s.pages == nil || d.work.seq == 0 {
+		s.deliver(d)
+		return
+	}
+
+	key := pageKey{d.work.Strategy, d.work.seq}
+	buf.held[key] = append(buf.held[key], d)
+	s.pages.done(key.strategy, key.seq)
+	s.release(buf, key.strategy)
+}
+
+// release delivers the items of the page being delivered for the strategy, moving on to the next pages as long as they are done.
+func (s *Scraper[T]) release(buf *reorderBuffer[T], strategy int) {
+	for {
+		key := pageKey{strategy, buf.head[strategy]}
+		for _, d := range buf.held[key] {
+			s.deliver(d)
+		}
+		delete(buf.held, key)
+
+		if !s.pages.complete(strategy, key.seq) {
+			return
+		}
+		buf.head[strategy]++
+	}
+}
+
+// releaseAll delivers the items of every strategy whose earlier pages are done.
+func (s *Scraper[T]) releaseAll(buf *reorderBuffer[T]) {
+	for strategy := range buf.head {
+		s.release(buf, strategy)
+	}
+}
+
+// keys returns the pages with held items, in the order of the pages of each strategy.
+func (b *reorderBuffer[T]) keys() []pageKey {
+	return slices.SortedFunc(maps.Keys(b.held), func(a, b pageKey) int {
+		if a.strategy != b.strategy {
+			return a.strategy - b.strategy
+		}
+		return a.seq - b.seq
+	})
+}
+
+// flush delivers every held item once the crawl has ended, in the order of the pages.
+func (s *Scraper[T]) flush(buf *reorderBuffer[T]) {
+	for _, key := range buf.keys() {
+		for _, d := range buf.held[key] {
+			s.deliver(d)
+		}
+	}
+	clear(buf.held)
+}
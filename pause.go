@@ -0,0 +1,74 @@
+package scrapify
+
+import "slices"
+
+// PauseDiscovery stops starting GetUrls calls on pages, while item URLs keep being fetched.
+// Pages already being processed finish and may still schedule item URLs and next pages, which wait in the queue.
+// Pausing discovery lets the item URLs of the queue drain, to bound memory or to let a backlog catch up.
+//
+// Discovery and fetching are paused independently: pausing both stops starting any work, so the crawl idles
+// until one of them is resumed. A paused crawl does not complete while paused work is pending, even once everything
+// else is done; it waits for the work to be resumed, or for the context given to Run to be cancelled.
+// It is safe to call while the crawl is running, and before it starts.
+func (s *Scraper[T]) PauseDiscovery() {
+	s.setPaused(PageWork, true)
+}
+
+// ResumeDiscovery starts processing pages again after PauseDiscovery.
+func (s *Scraper[T]) ResumeDiscovery() {
+	s.setPaused(PageWork, false)
+}
+
+// PauseFetching stops starting GetData calls on item URLs, while pages keep being discovered.
+// Item URLs already being fetched finish, and new ones wait in the queue. Pausing fetching stops hitting the item pages
+// of a host, for politeness, while the frontier keeps growing. See PauseDiscovery for the combination of both.
+func (s *Scraper[T]) PauseFetching() {
+	s.setPaused(ItemWork, true)
+}
+
+// ResumeFetching starts fetching item URLs again after PauseFetching.
+func (s *Scraper[T]) ResumeFetching() {
+	s.setPaused(ItemWork, false)
+}
+
+// setPaused pauses or resumes the work of the given kind, waking up the dispatcher on resume.
+func (s *Scraper[T]) setPaused(kind WorkKind, paused bool) {
+	s.mu.Lock()
+	if kind == PageWork {
+		s.pausedPages = paused
+	} else {
+		s.pausedItems = paused
+	}
+	s.mu.Unlock()
+
+	if !paused {
+		s.signal()
+	}
+}
+
+// isPaused reports whether work of the given kind is paused. It must be called with mu held.
+func (s *Scraper[T]) isPaused(kind WorkKind) bool {
+	if kind == PageWork {
+		return s.pausedPages
+	}
+	return s.pausedItems
+}
+
+// pop returns the next work that is not paused, resuming parked work first.
+// Paused work popped from the scheduler is parked until its kind is resumed. It must be called with mu held.
+func (s *Scraper[T]) pop() (Work, bool) {
+	for i, w := range s.parked {
+		if !s.isPaused(w.Kind) {
+			s.parked = slices.Delete(s.parked, i, i+1)
+			return w, true
+		}
+	}
+
+	for {
+		w, ok := s.scheduler.Pop()
+		if !ok || !s.isPaused(w.Kind) {
+			return w, ok
+		}
+		s.parked = append(s.parked, w)
+	}
+}
@@ -0,0 +1,60 @@
+package scrapify
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// prewarmTimeout bounds the time spent prewarming connections before the crawl starts.
+const prewarmTimeout = 10 * time.Second
+
+// Prewarmer can be implemented by an IScraper to open connections to the hosts of the start pages before the crawl starts,
+// with WithConnectionPrewarm. A scraper fetching pages with the fetch package can forward the call to fetch.Client.Prewarm.
+type Prewarmer interface {
+	// Prewarm opens a connection to the host of each URL, ignoring failures.
+	Prewarm(ctx context.Context, urls []string)
+}
+
+// WithConnectionPrewarm opens a connection to each distinct host of the start pages before the crawl starts,
+// through the scrapers implementing Prewarmer, to cut the latency of the first requests: connections are
+// established, TLS included, while no request waits for them. Each host is prewarmed once, by the scraper of the first
+// strategy starting on it, and all hosts at once, for up to 10 seconds. Prewarming is best-effort: failures are ignored,
+// and the crawl then opens its connections as usual. It is off by default.
+func WithConnectionPrewarm() Option {
+	return func(o *options) {
+		o.prewarmConns = true
+	}
+}
+
+// prewarm opens connections to the hosts of the start pages, if enabled, and returns once they are open or have failed.
+func (s *Scraper[T]) prewarm(ctx context.Context) {
+	if !s.prewarmConns {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, prewarmTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	origins := make(map[string]bool)
+	for i, strategy := range s.strategy {
+		p, ok := strategy.Scraper.(Prewarmer)
+		if !ok || !s.seeds(i) {
+			continue
+		}
+		u, err := url.Parse(strategy.Url)
+		if err != nil || origins[u.Scheme+"://"+u.Host] {
+			continue
+		}
+		origins[u.Scheme+"://"+u.Host] = true
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Prewarm(ctx, []string{strategy.Url})
+		}()
+	}
+	wg.Wait()
+}
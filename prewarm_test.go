@@ -0,0 +1,70 @@
+package scrapify_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+// prewarmingScraper records the URLs it prewarms and whether GetUrls was called before.
+type prewarmingScraper struct {
+	funcScraper[string]
+	mu         sync.Mutex
+	prewarmed  []string
+	discovered bool
+	late       bool
+}
+
+// Prewarm records the URLs, flagging a prewarm after discovery started.
+func (p *prewarmingScraper) Prewarm(ctx context.Context, urls []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.prewarmed = append(p.prewarmed, urls...)
+	p.late = p.late || p.discovered
+}
+
+// GetUrls records that discovery started.
+func (p *prewarmingScraper) GetUrls(ctx context.Context, url string) ([]string, []string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.discovered = true
+	return nil, nil, nil
+}
+
+func TestConnectionPrewarmOncePerHostBeforeCrawling(t *testing.T) {
+	scraper := &prewarmingScraper{}
+	crawl := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{
+		{Scraper: scraper, Url: "https://example.com/a"},
+		{Scraper: scraper, Url: "https://example.com/b"},
+		{Scraper: scraper, Url: "https://example.org/"},
+		{Scraper: funcScraper[string]{}, Url: "https://example.net/"},
+	}, nil, 0, scrapify.WithConnectionPrewarm())
+
+	if err := crawl.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	slices.Sort(scraper.prewarmed)
+	if want := []string{"https://example.com/a", "https://example.org/"}; !slices.Equal(scraper.prewarmed, want) {
+		t.Errorf("prewarmed %v, want %v", scraper.prewarmed, want)
+	}
+	if scraper.late {
+		t.Error("hosts were prewarmed after the crawl started")
+	}
+}
+
+func TestConnectionPrewarmIsOptIn(t *testing.T) {
+	scraper := &prewarmingScraper{}
+	crawl := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{Scraper: scraper, Url: "https://example.com/"}}, nil, 0)
+
+	if err := crawl.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(scraper.prewarmed) != 0 {
+		t.Errorf("prewarmed %v without WithConnectionPrewarm", scraper.prewarmed)
+	}
+}
@@ -0,0 +1,51 @@
+package scrapify
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNothingDiscovered is reported by Probe for a start page whose GetUrls call returned neither item URLs nor next pages.
+var ErrNothingDiscovered = errors.New("scrapify: no item URLs nor next pages discovered")
+
+// Probe checks that the crawl can start before committing to it: it validates the strategies and calls GetUrls once
+// on each start page, failing if the call fails or discovers nothing, which typically means the site changed and the scraper is stale.
+// Each failure is returned as a ScrapeError for the "probe" operation, wrapping ErrNothingDiscovered for empty start pages.
+// Calls are made one after the other, without rate limiting nor retries, and their context carries no CrawlHandle.
+// Nothing is scraped, visited or counted in Stats, so Run can follow a successful probe.
+func (s *Scraper[T]) Probe(ctx context.Context) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+
+	var errs []error
+	for i, strategy := range s.strategy {
+		w := Work{URL: strategy.Url, Strategy: i, Kind: PageWork}
+		if err := s.probe(ctx, w); err != nil {
+			errs = append(errs, &ScrapeError{Op: "probe", URL: w.URL, Strategy: i, StrategyID: strategy.ID, Err: err})
+		}
+		if ctx.Err() != nil {
+			return errors.Join(append(errs, ctx.Err())...)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// probe discovers the URLs of a start page and reports an error if there are none.
+func (s *Scraper[T]) probe(ctx context.Context, w Work) error {
+	ctx = s.withRequest(ctx, w)
+	if s.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.requestTimeout)
+		defer cancel()
+	}
+
+	urls, nextPages, err := discover(ctx, s.strategy[w.Strategy].Scraper, w.URL)
+	if err != nil {
+		return err
+	}
+	if len(urls) == 0 && len(nextPages) == 0 {
+		return ErrNothingDiscovered
+	}
+	return nil
+}
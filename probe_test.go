@@ -0,0 +1,28 @@
+package scrapify_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+func TestProbeErrorsNameTheirStrategy(t *testing.T) {
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{
+		{ID: "ok", Scraper: funcScraper[string]{urls: listing(1)}, Url: "https://example.com/ok"},
+		{ID: "stale", Scraper: funcScraper[string]{}, Url: "https://example.com/stale"},
+	}, nil, 0)
+
+	err := scraper.Probe(context.Background())
+	if !errors.Is(err, scrapify.ErrNothingDiscovered) {
+		t.Fatalf("Probe = %v, want ErrNothingDiscovered", err)
+	}
+	var scrapeErr *scrapify.ScrapeError
+	if !errors.As(err, &scrapeErr) {
+		t.Fatalf("Probe = %v, want a ScrapeError", err)
+	}
+	if scrapeErr.Strategy != 1 || scrapeErr.StrategyID != "stale" {
+		t.Errorf("ScrapeError strategy = %d %q, want 1 %q", scrapeErr.Strategy, scrapeErr.StrategyID, "stale")
+	}
+}
@@ -0,0 +1,82 @@
+package scrapify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"time"
+)
+
+// profileInterval is the interval between two samples of the runtime with WithPprof.
+const profileInterval = time.Second
+
+// WithPprof serves the net/http/pprof profiles on addr, such as "localhost:6060", while the crawl runs,
+// and samples the number of goroutines and the heap size every second into Stats.Goroutines and Stats.HeapAlloc.
+// The profiles are served under /debug/pprof/, and the current Stats, including the queue depth and in-flight work,
+// as JSON under /debug/scrapify/stats, to correlate profiles with the state of the crawl.
+// The server uses its own mux, so nothing is registered on http.DefaultServeMux. It is meant for development and off by default.
+func WithPprof(addr string) Option {
+	return func(o *options) {
+		o.pprofAddr = addr
+	}
+}
+
+// startProfiling starts the pprof server and the runtime sampling, if enabled, and returns a function stopping them.
+// It returns an error when the server cannot listen on its address.
+func (s *Scraper[T]) startProfiling() (func(), error) {
+	if s.pprofAddr == "" {
+		return func() {}, nil
+	}
+
+	ln, err := net.Listen("tcp", s.pprofAddr)
+	if err != nil {
+		return nil, fmt.Errorf("scrapify: starting pprof server: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/scrapify/stats", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(s.Stats())
+	})
+	srv := &http.Server{Handler: mux}
+	go func() { _ = srv.Serve(ln) }()
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+
+		for {
+			s.sample()
+			select {
+			case <-done:
+				return
+			case <-s.clock.After(profileInterval):
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+		_ = srv.Shutdown(context.Background())
+	}, nil
+}
+
+// sample records the number of goroutines and the heap size.
+func (s *Scraper[T]) sample() {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	s.counters.goroutines.Store(int64(runtime.NumGoroutine()))
+	s.counters.heapAlloc.Store(m.HeapAlloc)
+}
@@ -0,0 +1,79 @@
+package scrapify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Topics the items and the errors of a crawl are published to with WithPublisher.
+const (
+	ItemTopic  = "scrapify.items"  // Topic of the scraped items, each serialized to JSON.
+	ErrorTopic = "scrapify.errors" // Topic of the errors of the crawl, each serialized to a JSON PublishedError.
+)
+
+// EventPublisher publishes messages to a message queue, such as NATS or Kafka. The pubsub subpackage provides adapters.
+// It must be safe for concurrent use.
+type EventPublisher interface {
+	Publish(ctx context.Context, topic string, payload []byte) error
+}
+
+// PublisherFunc adapts a function to the EventPublisher interface.
+type PublisherFunc func(ctx context.Context, topic string, payload []byte) error
+
+// Publish calls f.
+func (f PublisherFunc) Publish(ctx context.Context, topic string, payload []byte) error {
+	return f(ctx, topic, payload)
+}
+
+// PublishedError is the JSON payload of an error published to ErrorTopic.
+type PublishedError struct {
+	Op         string `json:"op"`                    // Failed operation, see ScrapeError.Op.
+	URL        string `json:"url"`                   // URL being processed.
+	StrategyID string `json:"strategy_id,omitempty"` // ID of the strategy of the URL.
+	Depth      int    `json:"depth"`                 // Depth of the URL.
+	Error      string `json:"error"`                 // Message of the error returned by the scraper.
+}
+
+// WithPublisher publishes every item delivered, serialized to JSON, to ItemTopic, and every error reported, as a
+// PublishedError, to ErrorTopic, in addition to the callback and the hooks, so scraped data flows into downstream
+// systems without glue in the callback. Items are published right after the callback, in delivery order, so a slow
+// publisher slows down the delivery of items like a slow callback. Messages are still published while the crawl drains
+// after a cancellation. A failure to serialize or publish an item is reported as a ScrapeError for the "publish"
+// operation, which is not published itself. Nothing is published by default.
+func WithPublisher(publisher EventPublisher) Option {
+	return func(o *options) {
+		o.publisher = publisher
+	}
+}
+
+// publishItem publishes a delivered item with the WithPublisher publisher, if any.
+func (s *Scraper[T]) publishItem(w Work, item T) {
+	if s.publisher == nil {
+		return
+	}
+
+	payload, err := json.Marshal(item)
+	if err == nil {
+		err = s.publisher.Publish(context.WithoutCancel(s.runCtx), ItemTopic, payload)
+	}
+	if err != nil {
+		s.reportError(s.runCtx, "publish", w, err)
+	}
+}
+
+// publishError publishes a reported error with the WithPublisher publisher, if any, unless it is a publishing failure.
+func (s *Scraper[T]) publishError(err *ScrapeError) {
+	if s.publisher == nil || err.Op == "publish" {
+		return
+	}
+
+	payload, merr := json.Marshal(PublishedError{Op: err.Op, URL: err.URL, StrategyID: err.StrategyID, Depth: err.Depth, Error: err.Err.Error()})
+	if merr == nil {
+		merr = s.publisher.Publish(context.WithoutCancel(s.runCtx), ErrorTopic, payload)
+	}
+	if merr != nil {
+		w := Work{URL: err.URL, Strategy: err.Strategy, Depth: err.Depth}
+		s.reportError(s.runCtx, "publish", w, fmt.Errorf("publishing the %s error: %w", err.Op, merr))
+	}
+}
@@ -0,0 +1,84 @@
+package scrapify_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ricardocastanho/scrapify"
+	"github.com/ricardocastanho/scrapify/pubsub"
+)
+
+func TestPublisherPublishesItemsAndErrors(t *testing.T) {
+	var got collector[string]
+	publisher := pubsub.NewMemory()
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+		Scraper: funcScraper[string]{urls: listing(3), data: func(ctx context.Context, ch chan<- string, url string) error {
+			if strings.HasSuffix(url, "/2") {
+				return errors.New("no price")
+			}
+			return echo(ctx, ch, url)
+		}},
+		Url: "https://example.com",
+		ID:  "shop",
+	}}, got.add, 0, scrapify.WithPublisher(publisher))
+
+	if err := scraper.Run(context.Background()); err == nil {
+		t.Fatal("Run succeeded, want the error of the third item")
+	}
+
+	var items []string
+	for _, msg := range publisher.Messages(scrapify.ItemTopic) {
+		var item string
+		if err := json.Unmarshal(msg.Payload, &item); err != nil {
+			t.Fatalf("item payload %q: %v", msg.Payload, err)
+		}
+		items = append(items, item)
+	}
+	if !slices.Equal(items, got.result()) || len(items) != 2 {
+		t.Errorf("published items %v, want the delivered %v", items, got.result())
+	}
+
+	errs := publisher.Messages(scrapify.ErrorTopic)
+	if len(errs) != 1 {
+		t.Fatalf("published %d errors, want 1", len(errs))
+	}
+	var published scrapify.PublishedError
+	if err := json.Unmarshal(errs[0].Payload, &published); err != nil {
+		t.Fatal(err)
+	}
+	want := scrapify.PublishedError{Op: "get data", URL: "https://example.com/item/2", StrategyID: "shop", Depth: 1, Error: "no price"}
+	if published != want {
+		t.Errorf("published error %+v, want %+v", published, want)
+	}
+}
+
+func TestPublisherFailuresAreReported(t *testing.T) {
+	var calls atomic.Int64
+	failing := scrapify.PublisherFunc(func(ctx context.Context, topic string, payload []byte) error {
+		calls.Add(1)
+		return errors.New("queue down")
+	})
+	var got collector[string]
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+		Scraper: funcScraper[string]{urls: listing(2), data: echo},
+		Url:     "https://example.com",
+	}}, got.add, 0, scrapify.WithPublisher(failing))
+
+	err := scraper.Run(context.Background())
+	var scrapeErr *scrapify.ScrapeError
+	if !errors.As(err, &scrapeErr) || scrapeErr.Op != "publish" || !strings.Contains(err.Error(), "queue down") {
+		t.Errorf("Run error %v, want the publish failures", err)
+	}
+	if n := len(got.result()); n != 2 {
+		t.Errorf("delivered %d items, want 2 despite the failing publisher", n)
+	}
+	// Publishing failures are not published themselves.
+	if n := calls.Load(); n != 2 {
+		t.Errorf("published %d messages, want one per item", n)
+	}
+}
@@ -0,0 +1,95 @@
+// Package pubsub provides scrapify.EventPublisher implementations, to publish the items and errors of a crawl with
+// scrapify.WithPublisher: an in-memory Memory publisher, for tests and in-process consumers, and adapters for NATS and Kafka.
+//
+// It does not depend on any message queue library: the adapters publish through minimal interfaces, which the clients
+// of the common libraries implement directly or in a few lines.
+package pubsub
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+// Message is a message published to a Memory publisher.
+type Message struct {
+	Topic   string // Topic the message was published to.
+	Payload []byte // Payload of the message.
+}
+
+// Memory is an EventPublisher keeping the messages it is given in memory, in the order they were published.
+// It is safe for concurrent use.
+type Memory struct {
+	mu       sync.Mutex // Guards messages.
+	messages []Message  // Published messages, oldest first.
+}
+
+var _ scrapify.EventPublisher = (*Memory)(nil)
+
+// NewMemory creates an empty Memory publisher.
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+// Publish appends a copy of the message.
+func (m *Memory) Publish(ctx context.Context, topic string, payload []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, Message{Topic: topic, Payload: slices.Clone(payload)})
+	return nil
+}
+
+// Messages returns the messages published to topic so far, oldest first, or all of them for an empty topic.
+func (m *Memory) Messages(topic string) []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var messages []Message
+	for _, msg := range m.messages {
+		if topic == "" || msg.Topic == topic {
+			messages = append(messages, msg)
+		}
+	}
+	return messages
+}
+
+// NATSConn publishes a message to a NATS subject. *nats.Conn of github.com/nats-io/nats.go implements it.
+type NATSConn interface {
+	Publish(subject string, data []byte) error
+}
+
+// NATS returns an EventPublisher publishing each message to the NATS subject named after its topic.
+// The context is not passed on, since core NATS publishing does not block on the server.
+func NATS(conn NATSConn) scrapify.EventPublisher {
+	return scrapify.PublisherFunc(func(ctx context.Context, topic string, payload []byte) error {
+		return conn.Publish(topic, payload)
+	})
+}
+
+// KafkaWriter writes a message to a Kafka topic.
+type KafkaWriter interface {
+	WriteMessage(ctx context.Context, topic string, key, value []byte) error
+}
+
+// KafkaWriterFunc adapts a function to the KafkaWriter interface. With github.com/segmentio/kafka-go:
+//
+//	w := pubsub.KafkaWriterFunc(func(ctx context.Context, topic string, key, value []byte) error {
+//		return writer.WriteMessages(ctx, kafka.Message{Topic: topic, Key: key, Value: value})
+//	})
+type KafkaWriterFunc func(ctx context.Context, topic string, key, value []byte) error
+
+// WriteMessage calls f.
+func (f KafkaWriterFunc) WriteMessage(ctx context.Context, topic string, key, value []byte) error {
+	return f(ctx, topic, key, value)
+}
+
+// Kafka returns an EventPublisher writing each message to the Kafka topic named after its topic, without a key,
+// so the messages are spread over the partitions of the topic by the writer's balancer.
+func Kafka(w KafkaWriter) scrapify.EventPublisher {
+	return scrapify.PublisherFunc(func(ctx context.Context, topic string, payload []byte) error {
+		return w.WriteMessage(ctx, topic, nil, payload)
+	})
+}
@@ -0,0 +1,85 @@
+package scrapify
+
+import (
+	"net/url"
+	"strings"
+)
+
+// WithIgnoreQueryParams strips the given query parameters from every URL before it is deduplicated and scheduled,
+// such as tracking or facet parameters, so URLs differing only by them are fetched once. A name ending with * matches
+// every parameter starting with the rest of it, such as "utm_*". The other parameters are kept in their order.
+// Parameters are stripped after the URLNormalizer of WithURLNormalizer, if any, and from the start URLs as well,
+// so the scrapers receive the stripped URLs.
+func WithIgnoreQueryParams(params ...string) Option {
+	return func(o *options) {
+		o.ignoredParams = append(o.ignoredParams, params...)
+	}
+}
+
+// WithMaxQueryParams skips the discovered URLs with more than n query parameters, counting repeated ones, to keep
+// the crawl out of the combinatorial URLs of faceted navigation. Parameters are counted once stripped by WithIgnoreQueryParams,
+// so ignored ones do not count. Start URLs are never skipped. The default of zero is unlimited.
+func WithMaxQueryParams(n int) Option {
+	return func(o *options) {
+		o.maxQueryParams = n
+	}
+}
+
+// stripQueryParams removes the ignored query parameters from the URL, keeping the others as they are.
+func stripQueryParams(rawURL string, ignored []string) string {
+	if len(ignored) == 0 {
+		return rawURL
+	}
+
+	rest, fragment, hasFragment := strings.Cut(rawURL, "#")
+	base, query, ok := strings.Cut(rest, "?")
+	if !ok {
+		return rawURL
+	}
+
+	var kept []string
+	for _, param := range strings.Split(query, "&") {
+		name, _, _ := strings.Cut(param, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+		if param != "" && !ignoredParam(name, ignored) {
+			kept = append(kept, param)
+		}
+	}
+
+	if len(kept) > 0 {
+		base += "?" + strings.Join(kept, "&")
+	}
+	if hasFragment {
+		base += "#" + fragment
+	}
+	return base
+}
+
+// ignoredParam reports whether the query parameter matches one of the ignored names.
+func ignoredParam(name string, ignored []string) bool {
+	for _, pattern := range ignored {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(name, prefix) || pattern == name {
+			return true
+		}
+	}
+	return false
+}
+
+// tooManyParams reports whether the URL has more query parameters than allowed by WithMaxQueryParams.
+func (s *Scraper[T]) tooManyParams(rawURL string) bool {
+	if s.maxQueryParams <= 0 {
+		return false
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	n := 0
+	for _, values := range u.Query() {
+		n += len(values)
+	}
+	return n > s.maxQueryParams
+}
@@ -0,0 +1,103 @@
+package scrapify
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// quota allows at most max requests in any window of time.
+type quota struct {
+	max    int           // Maximum requests in the window.
+	window time.Duration // Length of the sliding window.
+}
+
+// WithHostQuota allows at most max requests to the host in any sliding window of the given length,
+// such as 60 requests per minute, matching how many sites document their limits.
+// Unlike WithRateLimit, which spaces out consecutive requests, a quota lets requests burst as long as the window total stays under max.
+// The host is matched against the host of each URL, including its port if any. Both GetUrls and GetData calls count as requests.
+func WithHostQuota(host string, max int, window time.Duration) Option {
+	return func(o *options) {
+		if o.hostQuotas == nil {
+			o.hostQuotas = make(map[string]quota)
+		}
+		o.hostQuotas[strings.ToLower(host)] = quota{max: max, window: window}
+	}
+}
+
+// WithDefaultHostQuota applies a quota of max requests per sliding window of the given length to every host
+// without its own quota set with WithHostQuota.
+func WithDefaultHostQuota(max int, window time.Duration) Option {
+	return func(o *options) {
+		o.defaultQuota = quota{max: max, window: window}
+	}
+}
+
+// quotaLimiter enforces the host quotas with a sliding log of the recent requests of each host.
+type quotaLimiter struct {
+	clock  Clock                  // Source of time.
+	quotas map[string]quota       // Quotas keyed by host.
+	def    quota                  // Quota of the other hosts (a zero max means none).
+	mu     sync.Mutex             // Guards the log map.
+	log    map[string][]time.Time // Start times of the requests made to each host within its window, oldest first.
+}
+
+// newQuotaLimiter creates a quotaLimiter, or returns nil when no quota is configured.
+func newQuotaLimiter(o options) *quotaLimiter {
+	if len(o.hostQuotas) == 0 && o.defaultQuota.max <= 0 {
+		return nil
+	}
+
+	return &quotaLimiter{
+		clock:  o.clock,
+		quotas: o.hostQuotas,
+		def:    o.defaultQuota,
+		log:    make(map[string][]time.Time),
+	}
+}
+
+// wait blocks until a request to the host fits in its quota, or until the context is done.
+func (l *quotaLimiter) wait(ctx context.Context, host string) error {
+	if l == nil {
+		return nil
+	}
+
+	host = strings.ToLower(host)
+	q, ok := l.quotas[host]
+	if !ok {
+		q = l.def
+	}
+	if q.max <= 0 || q.window <= 0 {
+		return nil
+	}
+
+	for {
+		l.mu.Lock()
+		now := l.clock.Now()
+
+		// Forget the requests that left the window.
+		times := l.log[host]
+		i := 0
+		for i < len(times) && !times[i].After(now.Add(-q.window)) {
+			i++
+		}
+		times = times[i:]
+
+		if len(times) < q.max {
+			l.log[host] = append(times, now)
+			l.mu.Unlock()
+			return nil
+		}
+		l.log[host] = times
+		d := times[0].Add(q.window).Sub(now)
+		l.mu.Unlock()
+
+		// Wait for the oldest request to leave the window, then try again.
+		select {
+		case <-l.clock.After(d):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package scrapify
+
+import (
+	"context"
+	"maps"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// BucketKeyer can be implemented by an IScraper to assign each URL to a rate-limit bucket.
+// URLs sharing a bucket key share a single rate limit, regardless of their host.
+type BucketKeyer interface {
+	// BucketKey returns the rate-limit bucket of the URL.
+	BucketKey(url string) string
+}
+
+// WithRateLimit limits the requests made to each rate-limit bucket to requestsPerSecond.
+// Both GetUrls and GetData calls count as requests. By default every host is its own bucket;
+// use WithBucketKey or implement BucketKeyer on the scraper to group or split hosts.
+// This limit is independent from the requestDelay given to NewScraper, which spaces out all requests globally.
+func WithRateLimit(requestsPerSecond float64) Option {
+	return func(o *options) {
+		o.rateLimit = requestsPerSecond
+	}
+}
+
+// WithBucketKey sets the function assigning each URL to a rate-limit bucket.
+// It takes precedence over a BucketKeyer implemented by the scraper. The default bucket key is the URL host.
+func WithBucketKey(fn func(url string) string) Option {
+	return func(o *options) {
+		o.bucketKey = fn
+	}
+}
+
+// WithMaxDiscoveryRate limits how fast URLs returned by GetUrls are admitted to the scheduler, to urlsPerSecond across the whole crawl.
+// Item URLs and next pages both count. It smooths the memory growth of the frontier on heavily interlinked sites.
+// Unlike WithRateLimit, which paces outbound requests, it paces the growth of pending work:
+// a page whose URLs are being admitted holds its worker until all of them are scheduled, so a low discovery rate also slows down discovery itself,
+// while fetching of already scheduled URLs continues at the pace allowed by the request rate limit.
+func WithMaxDiscoveryRate(urlsPerSecond float64) Option {
+	return func(o *options) {
+		o.discoveryRate = urlsPerSecond
+	}
+}
+
+// WithCallbackRateLimit limits the items delivered to the callback, and to the OnItem and OnItemMeta handlers, to perSecond,
+// for callbacks writing to a downstream with its own quota. Scraped items wait in a buffer of 256 items meanwhile, or of WithResultBuffer,
+// and once it is full, scrapers sending items block until the callback catches up. Items dropped by WithTransform are not paced.
+// Once the crawl is cancelled, pacing stops and the items still buffered are delivered right away, so Run returns promptly.
+// It is the output-side counterpart of WithRateLimit, which paces requests.
+func WithCallbackRateLimit(perSecond float64) Option {
+	return func(o *options) {
+		o.callbackRate = perSecond
+	}
+}
+
+// LimiterRegistry holds the rate limiters of the buckets of several Scraper instances, so crawls of the same process hitting
+// overlapping hosts respect the limit of each bucket together rather than each on its own. It is safe for concurrent use.
+type LimiterRegistry struct {
+	limiter *rateLimiter // Limiter shared by the scrapers (nil when unlimited).
+}
+
+// NewLimiterRegistry creates a LimiterRegistry allowing requestsPerSecond in each rate-limit bucket, across all the scrapers sharing it.
+// A requestsPerSecond that is not positive disables rate limiting.
+func NewLimiterRegistry(requestsPerSecond float64) *LimiterRegistry {
+	return &LimiterRegistry{limiter: newRateLimiter(requestsPerSecond, systemClock{})}
+}
+
+// WithSharedLimiter limits the requests of each rate-limit bucket with the limiters of registry, shared with the other scrapers
+// given the same registry, instead of a limiter of its own. It replaces WithRateLimit. Buckets are keyed as with WithRateLimit,
+// by host unless WithBucketKey or a BucketKeyer says otherwise, so scrapers sharing a registry should key them alike.
+// The shared limiters use the system clock, regardless of WithClock.
+func WithSharedLimiter(registry *LimiterRegistry) Option {
+	return func(o *options) {
+		o.sharedLimiter = registry
+	}
+}
+
+// WithDepthRateLimit limits the requests at each depth of limits to its requests per second in each rate-limit bucket,
+// such as to crawl cheap listing pages fast while throttling expensive detail pages. The start pages have a depth of 0,
+// see Work.Depth. The limit of a depth replaces WithRateLimit or WithSharedLimiter for the requests at that depth,
+// and the requests of each depth are spaced out independently of the other depths. A limit that is not positive leaves
+// its depth unlimited. Depths missing from limits fall back to WithRateLimit or WithSharedLimiter, if any.
+func WithDepthRateLimit(limits map[int]float64) Option {
+	return func(o *options) {
+		o.depthRateLimits = maps.Clone(limits)
+	}
+}
+
+// newDepthLimiters creates the rate limiters of the depths of WithDepthRateLimit, nil for unlimited depths.
+func newDepthLimiters(o options) map[int]*rateLimiter {
+	if len(o.depthRateLimits) == 0 {
+		return nil
+	}
+
+	limiters := make(map[int]*rateLimiter, len(o.depthRateLimits))
+	for depth, requestsPerSecond := range o.depthRateLimits {
+		limiters[depth] = newRateLimiter(requestsPerSecond, o.clock)
+	}
+	return limiters
+}
+
+// callbackBuffer is the number of scraped items buffered while the callback is paced by WithCallbackRateLimit.
+const callbackBuffer = 256
+
+// hostKey returns the host of the URL, which is the default rate-limit bucket key.
+func hostKey(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// rateLimiter spaces out requests so each bucket is accessed at most once per interval.
+type rateLimiter struct {
+	clock    Clock                // Source of time.
+	mu       sync.Mutex           // Guards the next map.
+	interval time.Duration        // Minimum time between two requests in the same bucket.
+	next     map[string]time.Time // Earliest time the next request of each bucket may start.
+}
+
+// newRateLimiter creates a rateLimiter allowing requestsPerSecond in each bucket.
+// It returns nil when requestsPerSecond is not positive, which disables rate limiting.
+func newRateLimiter(requestsPerSecond float64, clock Clock) *rateLimiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	return newIntervalLimiter(time.Duration(float64(time.Second)/requestsPerSecond), clock)
+}
+
+// newIntervalLimiter creates a rateLimiter spacing the requests of each bucket by interval.
+// It returns nil when interval is not positive, which disables rate limiting.
+func newIntervalLimiter(interval time.Duration, clock Clock) *rateLimiter {
+	if interval <= 0 {
+		return nil
+	}
+
+	return &rateLimiter{
+		clock:    clock,
+		interval: interval,
+		next:     make(map[string]time.Time),
+	}
+}
+
+// wait blocks until a request in the given bucket is allowed, or until the context is done.
+func (l *rateLimiter) wait(ctx context.Context, key string) error {
+	if l == nil {
+		return nil
+	}
+	return l.waitInterval(ctx, key, l.interval)
+}
+
+// waitInterval is like wait, spacing the requests of the bucket by the given interval instead of the limiter's.
+func (l *rateLimiter) waitInterval(ctx context.Context, key string, interval time.Duration) error {
+	// Reserve the next free slot of the bucket.
+	l.mu.Lock()
+	now := l.clock.Now()
+	slot := l.next[key]
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next[key] = slot.Add(interval)
+	l.mu.Unlock()
+
+	d := slot.Sub(now)
+	if d <= 0 {
+		return nil
+	}
+
+	select {
+	case <-l.clock.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// bucketOf returns the rate-limit bucket of a URL scraped by the given scraper.
+func (s *Scraper[T]) bucketOf(scraper IScraper[T], url string) string {
+	if s.bucketKey != nil {
+		return s.bucketKey(url)
+	}
+	if k, ok := scraper.(BucketKeyer); ok {
+		return k.BucketKey(url)
+	}
+	return hostKey(url)
+}
+
+// throttle waits for the backoff requested by a RetryAfterError for the URL's host, then for the rate limiter of its bucket
+// at the depth of the work, and finally for the crawl delay, the latency-aware delay and the quota of its host.
+// It is called before the timeout of the call is set, see WithRequestTimeout.
+func (s *Scraper[T]) throttle(ctx context.Context, scraper IScraper[T], w Work) error {
+	if err := s.backoffs.wait(ctx, hostKey(w.URL)); err != nil {
+		return err
+	}
+	limiter := s.limiter
+	if l, ok := s.depthLimiters[w.Depth]; ok {
+		limiter = l
+	}
+	if limiter != nil {
+		if err := limiter.wait(ctx, s.bucketOf(scraper, w.URL)); err != nil {
+			return err
+		}
+	}
+	if s.crawlDelays != nil {
+		if err := s.crawlDelays.wait(ctx, s.runCtx, w.URL); err != nil {
+			return err
+		}
+	}
+	if s.latency != nil {
+		if err := s.latency.wait(ctx, hostKey(w.URL)); err != nil {
+			return err
+		}
+	}
+	if s.quotas != nil {
+		return s.quotas.wait(ctx, hostKey(w.URL))
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package scrapify_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+// crawlDuration runs a crawl of a listing page with n item URLs and returns how long it took.
+func crawlDuration(t *testing.T, n int, opts ...scrapify.Option) time.Duration {
+	t.Helper()
+
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+		Scraper: funcScraper[string]{urls: listing(n), data: echo},
+		Url:     "https://example.com",
+	}}, nil, 0, append(opts, scrapify.WithConcurrency(n))...)
+
+	start := time.Now()
+	if err := scraper.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	return time.Since(start)
+}
+
+func TestDepthRateLimitThrottlesItsDepth(t *testing.T) {
+	// The 10 item URLs, at depth 1, are spaced out by 40ms; the start page is not.
+	if d := crawlDuration(t, 10, scrapify.WithDepthRateLimit(map[int]float64{1: 25})); d < 360*time.Millisecond {
+		t.Errorf("crawl took %v, want the item URLs limited to 25 per second", d)
+	}
+}
+
+func TestDepthRateLimitReplacesRateLimit(t *testing.T) {
+	// Without the depth limit, the item URLs would be spaced out by a second.
+	if d := crawlDuration(t, 5, scrapify.WithRateLimit(1), scrapify.WithDepthRateLimit(map[int]float64{1: 0})); d > 500*time.Millisecond {
+		t.Errorf("crawl took %v, want the item URLs unlimited", d)
+	}
+}
+
+func TestDepthRateLimitFallsBackToRateLimit(t *testing.T) {
+	// Depth 1 is not listed, so its item URLs are limited by WithRateLimit.
+	if d := crawlDuration(t, 5, scrapify.WithRateLimit(25), scrapify.WithDepthRateLimit(map[int]float64{0: 1000})); d < 160*time.Millisecond {
+		t.Errorf("crawl took %v, want the item URLs limited to 25 per second", d)
+	}
+}
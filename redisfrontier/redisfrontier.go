@@ -0,0 +1,271 @@
+// Package redisfrontier shares the frontier and the visited set of a crawl among several processes through Redis,
+// implementing scrapify.Frontier and scrapify.VisitedStore.
+//
+// It does not depend on any Redis library: it sends commands through the minimal Client interface, which is a few
+// lines to implement on top of any client. With github.com/redis/go-redis/v9:
+//
+//	client := redisfrontier.ClientFunc(func(ctx context.Context, args ...any) (any, error) {
+//		return rdb.Do(ctx, args...).Result()
+//	})
+//
+// The frontier requires Redis 6.2 or later, for LMOVE.
+package redisfrontier
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+// Client sends a command to Redis and returns its reply: nil for a nil reply, and int64, string or []any otherwise.
+type Client interface {
+	Do(ctx context.Context, args ...any) (any, error)
+}
+
+// ClientFunc adapts a function to the Client interface.
+type ClientFunc func(ctx context.Context, args ...any) (any, error)
+
+// Do calls f.
+func (f ClientFunc) Do(ctx context.Context, args ...any) (any, error) {
+	return f(ctx, args...)
+}
+
+// Option configures a Frontier or a VisitedStore.
+type Option func(*config)
+
+// config holds the optional configuration of a Frontier or a VisitedStore.
+type config struct {
+	ctx     context.Context // Context of the commands sent to Redis.
+	worker  string          // Name of the process, identifying its claims.
+	onError func(error)     // Invoked with the errors of the commands.
+}
+
+// WithContext sets the context of the commands sent to Redis, such as one bounding them with a deadline. The default is context.Background().
+func WithContext(ctx context.Context) Option {
+	return func(c *config) {
+		c.ctx = ctx
+	}
+}
+
+// WithWorker sets the name of the process, under which its claims are held. Names must be unique among the processes of a crawl
+// and stable across restarts, so a restarted process can Requeue its own claims. The default is the host name and the process ID.
+func WithWorker(name string) Option {
+	return func(c *config) {
+		c.worker = name
+	}
+}
+
+// OnError registers a hook invoked with the error of every failed Redis command.
+// The scrapify.Scheduler and scrapify.VisitedStore interfaces cannot return errors, so failures are reported through this hook:
+// a failed push drops the work, a failed pop returns no work, and a failed visit skips the URL.
+func OnError(fn func(error)) Option {
+	return func(c *config) {
+		c.onError = fn
+	}
+}
+
+// newConfig applies the options to the default configuration.
+func newConfig(opts []Option) config {
+	host, _ := os.Hostname()
+	c := config{ctx: context.Background(), worker: host + "-" + strconv.Itoa(os.Getpid())}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// report passes a non-nil error to the OnError hook, if any.
+func (c *config) report(err error) {
+	if err != nil && c.onError != nil {
+		c.onError(err)
+	}
+}
+
+// claimScript atomically moves the oldest pending work to the claims of the worker and counts the claim.
+const claimScript = `local w = redis.call('LMOVE', KEYS[1], KEYS[2], 'RIGHT', 'LEFT')
+if w then redis.call('INCR', KEYS[3]) end
+return w`
+
+// ackScript atomically removes work from the claims of the worker and uncounts the claim.
+const ackScript = `if redis.call('LREM', KEYS[1], 1, ARGV[1]) > 0 then redis.call('DECR', KEYS[2]) end
+return 1`
+
+// requeueScript atomically moves every claim of a worker back to the pending work and uncounts them.
+const requeueScript = `local n = 0
+while redis.call('LMOVE', KEYS[1], KEYS[2], 'RIGHT', 'RIGHT') do n = n + 1 end
+if n > 0 then redis.call('DECRBY', KEYS[3], n) end
+return n`
+
+// Frontier is a scrapify.Frontier storing the pending work of a crawl in Redis, in discovery order.
+// Pending work is a list under <prefix>:queue, the claims of each worker a list under <prefix>:claims:<worker>,
+// and the number of claims of all workers a counter under <prefix>:claimed.
+// Pass it to every process of the crawl with scrapify.WithScheduler, along with a shared VisitedStore.
+type Frontier struct {
+	client  Client // Connection to Redis.
+	queue   string // Key of the pending work.
+	claims  string // Key of the work claimed by this worker.
+	claimed string // Key of the number of claims of all workers.
+	prefix  string // Prefix of the keys.
+	config         // Optional configuration.
+}
+
+// NewFrontier creates a Frontier storing its keys under prefix.
+func NewFrontier(client Client, prefix string, opts ...Option) *Frontier {
+	c := newConfig(opts)
+	return &Frontier{
+		client:  client,
+		queue:   prefix + ":queue",
+		claims:  prefix + ":claims:" + c.worker,
+		claimed: prefix + ":claimed",
+		prefix:  prefix,
+		config:  c,
+	}
+}
+
+// Push appends the work to the pending work of the crawl.
+func (f *Frontier) Push(w scrapify.Work) bool {
+	payload, err := json.Marshal(w)
+	if err == nil {
+		_, err = f.client.Do(f.ctx, "LPUSH", f.queue, payload)
+	}
+	if err != nil {
+		f.report(fmt.Errorf("redisfrontier: pushing %s: %w", w.URL, err))
+		return false
+	}
+	return true
+}
+
+// Pop claims the oldest pending work for this worker.
+func (f *Frontier) Pop() (scrapify.Work, bool) {
+	reply, err := f.client.Do(f.ctx, "EVAL", claimScript, 3, f.queue, f.claims, f.claimed)
+	if err != nil {
+		f.report(fmt.Errorf("redisfrontier: claiming work: %w", err))
+		return scrapify.Work{}, false
+	}
+	if reply == nil {
+		return scrapify.Work{}, false
+	}
+
+	var w scrapify.Work
+	if err := json.Unmarshal([]byte(str(reply)), &w); err != nil {
+		f.report(fmt.Errorf("redisfrontier: decoding work: %w", err))
+		return scrapify.Work{}, false
+	}
+	return w, true
+}
+
+// Len returns the number of pending work items of the crawl, excluding claimed ones.
+func (f *Frontier) Len() int {
+	n, err := integer(f.client.Do(f.ctx, "LLEN", f.queue))
+	if err != nil {
+		f.report(fmt.Errorf("redisfrontier: counting pending work: %w", err))
+	}
+	return n
+}
+
+// Ack releases the claim of this worker on the work.
+func (f *Frontier) Ack(w scrapify.Work) {
+	payload, err := json.Marshal(w)
+	if err == nil {
+		_, err = f.client.Do(f.ctx, "EVAL", ackScript, 2, f.claims, f.claimed, payload)
+	}
+	if err != nil {
+		f.report(fmt.Errorf("redisfrontier: acknowledging %s: %w", w.URL, err))
+	}
+}
+
+// Claimed returns the number of work items claimed by all workers and not acknowledged yet.
+func (f *Frontier) Claimed() int {
+	n, err := integer(f.client.Do(f.ctx, "GET", f.claimed))
+	if err != nil {
+		f.report(fmt.Errorf("redisfrontier: counting claims: %w", err))
+	}
+	return n
+}
+
+// Requeue moves the claims of the given worker back to the pending work, so the work of a crashed or cancelled process
+// is handed out again. It returns the number of requeued work items. A restarted process can requeue its own
+// claims before crawling, provided it keeps its name with WithWorker.
+func (f *Frontier) Requeue(ctx context.Context, worker string) (int, error) {
+	n, err := integer(f.client.Do(ctx, "EVAL", requeueScript, 3, f.prefix+":claims:"+worker, f.queue, f.claimed))
+	if err != nil {
+		return 0, fmt.Errorf("redisfrontier: requeuing the claims of %s: %w", worker, err)
+	}
+	return n, nil
+}
+
+// integer converts the integer reply of a command, which is a string for GET, to an int. A nil reply is zero.
+func integer(reply any, err error) (int, error) {
+	if err != nil {
+		return 0, err
+	}
+
+	switch v := reply.(type) {
+	case nil:
+		return 0, nil
+	case int64:
+		return int(v), nil
+	default:
+		n, err := strconv.Atoi(str(v))
+		if err != nil {
+			return 0, fmt.Errorf("unexpected reply %v", reply)
+		}
+		return n, nil
+	}
+}
+
+// VisitedStore is a scrapify.VisitedStore keeping the visited URLs of a crawl in a Redis set, shared by all its processes.
+// Visit is atomic, so a URL visited by several processes at once is only reported as new to one of them.
+type VisitedStore struct {
+	client Client // Connection to Redis.
+	key    string // Key of the set.
+	config        // Optional configuration.
+}
+
+// NewVisitedStore creates a VisitedStore keeping the visited URLs in the set under key.
+func NewVisitedStore(client Client, key string, opts ...Option) *VisitedStore {
+	return &VisitedStore{client: client, key: key, config: newConfig(opts)}
+}
+
+// Visit marks the URL as visited and reports whether it was newly added. It returns false when the command fails,
+// so the URL is skipped rather than possibly scraped twice.
+func (s *VisitedStore) Visit(url string) bool {
+	reply, err := s.client.Do(s.ctx, "SADD", s.key, url)
+	if err != nil {
+		s.report(fmt.Errorf("redisfrontier: visiting %s: %w", url, err))
+		return false
+	}
+	return reply == int64(1)
+}
+
+// Visited reports whether the URL has already been marked as visited. It returns false when the command fails.
+func (s *VisitedStore) Visited(url string) bool {
+	reply, err := s.client.Do(s.ctx, "SISMEMBER", s.key, url)
+	if err != nil {
+		s.report(fmt.Errorf("redisfrontier: checking %s: %w", url, err))
+		return false
+	}
+	return reply == int64(1)
+}
+
+// str returns a bulk string reply, which clients return either as a string or as bytes.
+func str(reply any) string {
+	switch v := reply.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Compile-time checks of the implemented interfaces.
+var (
+	_ scrapify.Frontier     = (*Frontier)(nil)
+	_ scrapify.VisitedStore = (*VisitedStore)(nil)
+)
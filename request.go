@@ -0,0 +1,37 @@
+package scrapify
+
+import (
+	"context"
+	"net/http"
+)
+
+// Request describes a non-GET request used to fetch the start page of a strategy, such as a search submitted with POST.
+type Request struct {
+	URL    string      // URL the request applies to, set to the strategy URL by the Scraper.
+	Method string      // HTTP method, such as http.MethodPost.
+	Header http.Header // Headers to send with the request.
+	Body   []byte      // Request body.
+}
+
+// requestKey is the context key under which the Request of the current page is stored.
+type requestKey struct{}
+
+// RequestFromContext returns the Request to use for the page being processed, if its strategy defines one.
+// It is only set in the context of the calls processing the strategy's start page: GetUrls, and GetData with SeedLinksAndData.
+// The fetch client applies it automatically when fetching that URL.
+func RequestFromContext(ctx context.Context) (*Request, bool) {
+	r, ok := ctx.Value(requestKey{}).(*Request)
+	return r, ok
+}
+
+// withRequest stores the Request of the strategy in the context when the work is on the strategy's start URL.
+func (s *Scraper[T]) withRequest(ctx context.Context, w Work) context.Context {
+	strategy := s.strategy[w.Strategy]
+	if strategy.Request == nil || w.URL != strategy.Url {
+		return ctx
+	}
+
+	r := *strategy.Request
+	r.URL = strategy.Url
+	return context.WithValue(ctx, requestKey{}, &r)
+}
@@ -0,0 +1,75 @@
+package scrapify
+
+import "time"
+
+// URLResult records how a page or item URL was processed, for crawl analytics.
+// The response fields describe the last attempt, as reported with RecordResponse, and are zero if none was recorded.
+type URLResult struct {
+	URL           string        // The processed URL.
+	Kind          WorkKind      // Whether the URL was a page or an item URL.
+	Strategy      int           // Index of the strategy of the URL in the list given to NewScraper.
+	StrategyID    string        // ID of the strategy of the URL.
+	Depth         int           // Depth of the URL, see Work.Depth.
+	StatusCode    int           // Status code of the response.
+	Bytes         int64         // Size of the response body, after decompression.
+	Duration      time.Duration // Time from the start of the last attempt to its response, or to its end if none was recorded.
+	Items         int           // Items sent by GetData, for item URLs.
+	URLs          int           // Item URLs and next pages returned by GetUrls, for pages.
+	Retries       int           // Retries of the call.
+	RedirectChain []Redirect    // Redirect responses followed to reach the recorded response, oldest first (nil without redirects).
+	Err           error         // Error of the last attempt, nil on success.
+}
+
+// URLResults enables per-URL results and returns the channel they are sent on, one for every GetUrls and GetData call once it is done,
+// retries included. The channel is closed when the crawl ends.
+// It must be called before Run or Start, and the channel must be drained until it is closed, otherwise the crawl blocks.
+// Results are not recorded unless URLResults is called.
+func (s *Scraper[T]) URLResults() <-chan URLResult {
+	if s.results == nil {
+		s.results = make(chan URLResult)
+	}
+	return s.results
+}
+
+// sendResult sends the result of the work on the URLResults channel, if enabled.
+func (s *Scraper[T]) sendResult(w Work, rec *recorder, attempts, urls int, err error) {
+	if s.results == nil {
+		return
+	}
+
+	r := URLResult{URL: w.URL, Kind: w.Kind, Strategy: w.Strategy, StrategyID: s.strategy[w.Strategy].ID, Depth: w.Depth, URLs: urls, Retries: max(attempts-1, 0), Err: err}
+	if rec != nil {
+		r.Duration = rec.latency()
+
+		rec.mu.Lock()
+		r.StatusCode = rec.info.StatusCode
+		r.Bytes = rec.info.Bytes
+		r.RedirectChain = rec.info.RedirectChain
+		r.Items = rec.items
+		rec.mu.Unlock()
+	}
+	s.resultsMu.RLock()
+	defer s.resultsMu.RUnlock()
+
+	if s.resultsClosed {
+		return
+	}
+	select {
+	case s.results <- r:
+	case <-s.abandoned:
+	}
+}
+
+// closeResults closes the URLResults channel, if enabled, once the crawl has ended.
+func (s *Scraper[T]) closeResults() {
+	if s.results == nil {
+		return
+	}
+
+	// Senders blocked on a channel no longer drained are released by the abandonment of their work first.
+	s.resultsMu.Lock()
+	defer s.resultsMu.Unlock()
+
+	s.resultsClosed = true
+	close(s.results)
+}
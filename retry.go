@@ -0,0 +1,163 @@
+package scrapify
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// JitterStrategy computes the delay before a retry.
+// base and maxDelay are the bounds configured with WithRetry, prev is the delay returned for the previous retry of the same URL
+// (zero before the first retry), and attempt is the number of retries already made.
+type JitterStrategy func(base, maxDelay, prev time.Duration, attempt int) time.Duration
+
+// exponential returns base doubled for every attempt, capped at maxDelay.
+func exponential(base, maxDelay time.Duration, attempt int) time.Duration {
+	d := base
+	for i := 0; i < attempt && d < maxDelay; i++ {
+		d *= 2
+	}
+	return min(d, maxDelay)
+}
+
+// randomBetween returns a random duration in [lo, hi].
+func randomBetween(lo, hi time.Duration) time.Duration {
+	if hi <= lo {
+		return lo
+	}
+	return lo + rand.N(hi-lo+1)
+}
+
+// NoJitter waits exactly the exponential backoff. Retries of URLs that failed together stay synchronized.
+func NoJitter(base, maxDelay, prev time.Duration, attempt int) time.Duration {
+	return exponential(base, maxDelay, attempt)
+}
+
+// FullJitter waits a random delay between zero and the exponential backoff.
+// It spreads retries the most and is the default strategy.
+func FullJitter(base, maxDelay, prev time.Duration, attempt int) time.Duration {
+	return randomBetween(0, exponential(base, maxDelay, attempt))
+}
+
+// EqualJitter waits half of the exponential backoff plus a random delay up to the other half,
+// which guarantees a minimum wait while still spreading retries.
+func EqualJitter(base, maxDelay, prev time.Duration, attempt int) time.Duration {
+	half := exponential(base, maxDelay, attempt) / 2
+	return half + randomBetween(0, half)
+}
+
+// DecorrelatedJitter waits a random delay between base and three times the previous delay, capped at maxDelay.
+// Each delay depends on the previous one rather than on the attempt number.
+func DecorrelatedJitter(base, maxDelay, prev time.Duration, attempt int) time.Duration {
+	prev = max(prev, base)
+	return min(randomBetween(base, prev*3), maxDelay)
+}
+
+// WithRetry retries a failed GetUrls or GetData call up to maxRetries times, waiting an exponential backoff
+// starting at base and capped at maxDelay between attempts. The backoff is randomized with FullJitter unless
+// another strategy is set with WithBackoffJitter. Each attempt goes through the rate limiter.
+// A GetData call is retried as a whole, so a scraper sending items before failing may deliver them more than once.
+func WithRetry(maxRetries int, base, maxDelay time.Duration) Option {
+	return func(o *options) {
+		o.maxRetries = maxRetries
+		o.retryBase = base
+		o.retryMax = maxDelay
+	}
+}
+
+// WithDiscoveryRetries sets the number of retries of a failed GetUrls call, overriding the maxRetries of WithRetry,
+// whose backoff still applies. A failed page loses all the URLs it would have discovered, so discovery usually
+// deserves more retries than items. By default GetUrls calls are retried as many times as set by WithRetry.
+func WithDiscoveryRetries(n int) Option {
+	return func(o *options) {
+		o.discoveryRetries = n
+	}
+}
+
+// WithDataRetries sets the number of retries of a failed GetData call, overriding the maxRetries of WithRetry,
+// whose backoff still applies. By default GetData calls are retried as many times as set by WithRetry.
+func WithDataRetries(n int) Option {
+	return func(o *options) {
+		o.dataRetries = n
+	}
+}
+
+// WithRetryDeadline bounds the time spent retrying a URL: once d has elapsed since its first attempt,
+// or the backoff before the next retry would end past d, the URL is given up on with its last error.
+// It complements WithRetry, which still sets the backoff and the maximum number of retries: whichever limit is reached first
+// stops retrying, so a large maxRetries leaves the deadline alone in charge. The default of zero is no deadline.
+func WithRetryDeadline(d time.Duration) Option {
+	return func(o *options) {
+		o.retryDeadline = d
+	}
+}
+
+// retries returns the number of retries of the calls of the given kind of work.
+func (s *Scraper[T]) retries(kind WorkKind) int {
+	n := s.dataRetries
+	if kind == PageWork {
+		n = s.discoveryRetries
+	}
+	if n < 0 {
+		return s.maxRetries
+	}
+	return n
+}
+
+// WithBackoffJitter sets the strategy randomizing the delay between retries.
+// Use FullJitter, EqualJitter, DecorrelatedJitter, NoJitter or a custom JitterStrategy.
+func WithBackoffJitter(strategy JitterStrategy) Option {
+	return func(o *options) {
+		if strategy != nil {
+			o.jitter = strategy
+		}
+	}
+}
+
+// retry calls fn on the URL of the work until it succeeds, the retries are exhausted or the context is done, and returns its last error.
+// Permanent errors, such as ErrFiltered, are returned without retrying. A RetryAfterError backs off the host of the URL
+// and replaces the backoff before the next retry with its delay. Retrying stops early when the backoff would end past
+// the WithRetryDeadline of the URL or the deadline of the context.
+func (s *Scraper[T]) retry(ctx context.Context, w Work, fn func() error) error {
+	maxRetries := s.retries(w.Kind)
+	start := s.clock.Now()
+	var prev time.Duration
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		s.adapt(ctx, err)
+		delay, after := retryAfter(err)
+		if after {
+			s.backoffs.backoff(hostKey(w.URL), delay)
+		}
+		if err == nil || attempt >= maxRetries || ctx.Err() != nil || permanent(err) {
+			return err
+		}
+
+		if after {
+			prev = delay
+		} else {
+			prev = s.jitter(s.retryBase, s.retryMax, prev, attempt)
+		}
+		if !s.retryable(ctx, start, prev) {
+			return err
+		}
+		s.counters.retries.Add(1)
+		s.strategyStats[w.Strategy].retries.Add(1)
+		select {
+		case <-s.clock.After(prev):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// retryable reports whether a retry waiting delay from now still starts within the WithRetryDeadline of a URL first attempted
+// at start, and before the deadline of the context.
+func (s *Scraper[T]) retryable(ctx context.Context, start time.Time, delay time.Duration) bool {
+	next := s.clock.Now().Add(delay)
+	if s.retryDeadline > 0 && next.Sub(start) > s.retryDeadline {
+		return false
+	}
+	deadline, ok := ctx.Deadline()
+	return !ok || next.Before(deadline)
+}
@@ -0,0 +1,89 @@
+package scrapify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RetryAfterError is returned by a scraper to ask for a precise delay before the next request to the host of the URL,
+// typically the Retry-After header of a 429 or 503 response.
+// The retry of the call waits exactly Delay instead of the backoff of WithRetry, and every other request to the same host
+// waits until the delay has elapsed too, so the whole host slows down rather than the one URL.
+// The host is backed off even when the call is not retried.
+type RetryAfterError struct {
+	Delay time.Duration // How long to wait before the next request to the host.
+	Err   error         // Underlying error, such as ErrRateLimited.
+}
+
+// RetryAfter returns a RetryAfterError asking to wait d before the next request to the host of the failed URL.
+func RetryAfter(d time.Duration, err error) error {
+	return &RetryAfterError{Delay: d, Err: err}
+}
+
+// Error describes the error and the requested delay.
+func (e *RetryAfterError) Error() string {
+	return fmt.Sprintf("scrapify: retry after %s: %v", e.Delay, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// retryAfter returns the delay requested by err, if it wraps a RetryAfterError.
+func retryAfter(err error) (time.Duration, bool) {
+	var ra *RetryAfterError
+	if !errors.As(err, &ra) {
+		return 0, false
+	}
+	return max(ra.Delay, 0), true
+}
+
+// hostBackoffs holds the hosts that asked, through a RetryAfterError, not to be requested before a given time.
+type hostBackoffs struct {
+	clock Clock                // Source of time.
+	mu    sync.Mutex           // Guards until.
+	until map[string]time.Time // Time before which each backed-off host must not be requested.
+}
+
+// newHostBackoffs creates an empty hostBackoffs.
+func newHostBackoffs(clock Clock) *hostBackoffs {
+	return &hostBackoffs{clock: clock, until: make(map[string]time.Time)}
+}
+
+// backoff keeps the host from being requested for d, unless it is already backed off for longer.
+func (b *hostBackoffs) backoff(host string, d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if until := b.clock.Now().Add(d); until.After(b.until[host]) {
+		b.until[host] = until
+	}
+}
+
+// wait blocks until the host is no longer backed off, or until the context is done.
+func (b *hostBackoffs) wait(ctx context.Context, host string) error {
+	for {
+		b.mu.Lock()
+		until, ok := b.until[host]
+		d := until.Sub(b.clock.Now())
+		if ok && d <= 0 {
+			delete(b.until, host)
+		}
+		b.mu.Unlock()
+
+		if d <= 0 {
+			return nil
+		}
+
+		// Wait again afterwards, since another response may have extended the backoff meanwhile.
+		select {
+		case <-b.clock.After(d):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,418 @@
+package scrapify
+
+import (
+	"container/heap"
+	"context"
+	"time"
+)
+
+// WorkKind tells what has to be done with the URL of a Work item.
+type WorkKind string
+
+const (
+	// PageWork is a page whose item URLs and next pages are discovered with GetUrls.
+	PageWork WorkKind = "page"
+
+	// ItemWork is an item URL whose data is scraped with GetData.
+	ItemWork WorkKind = "item"
+)
+
+// Work is a unit of work handled by a Scheduler.
+type Work struct {
+	URL      string        `json:"url"`               // The URL to process.
+	Strategy int           `json:"strategy"`          // Index of the originating strategy in the list given to NewScraper.
+	Kind     WorkKind      `json:"kind"`              // What to do with the URL.
+	Timeout  time.Duration `json:"timeout,omitempty"` // Per-request timeout overriding the default one, when positive.
+	Depth    int           `json:"depth,omitempty"`   // Number of hops from the start page of the strategy, which has a depth of 0.
+	seq      int           // Sequence number of the page of the work in its strategy, with WithOrderedPagination (0 if unordered).
+}
+
+// Scheduler decides which work the Scraper executes next.
+// Implementations control both admission (which work is accepted) and ordering (which work is executed first).
+// The Scraper serializes all calls, so implementations do not need to be safe for concurrent use.
+type Scheduler interface {
+	// Push offers new work to the scheduler and reports whether it was accepted.
+	// Rejected work is dropped and never executed.
+	Push(w Work) bool
+
+	// Pop removes and returns the next work to execute.
+	// It returns false when no work is pending.
+	Pop() (Work, bool)
+
+	// Len returns the number of pending work items.
+	Len() int
+}
+
+// Frontier is a Scheduler shared by several Scraper processes, to distribute a crawl among them.
+// Pop atomically claims the work for the calling process, so no two processes execute the same work,
+// and the Scraper acknowledges every popped work once it is done. Combined with a VisitedStore shared by the same processes,
+// whose Visit atomically marks a URL, every page and item URL is processed once across the whole crawl.
+// Every process must run the same strategies, in the same order, since work refers to its strategy by index.
+//
+// A process stops once the frontier is empty, it has nothing in flight and no other process holds a claim.
+// Claimed work may still discover new URLs, so the process only stops if the frontier is still in that state half a second later.
+// Work pushed by other processes does not wake up the dispatcher, so the frontier is also polled every half second while the process waits.
+// Work interrupted by the cancellation of the crawl is not acknowledged, so the frontier can hand it out again.
+type Frontier interface {
+	Scheduler
+
+	// Ack releases the claim on work popped by this process, once it is done.
+	Ack(w Work)
+
+	// Claimed returns the number of work items popped by any process and not acknowledged yet.
+	Claimed() int
+}
+
+// frontierPoll is the interval at which a Frontier is polled for work pushed by other processes.
+const frontierPoll = 500 * time.Millisecond
+
+// FIFOScheduler executes work in the order it was discovered, which is a breadth-first crawl.
+// It is the default Scheduler.
+type FIFOScheduler struct {
+	queue []Work // Pending work, oldest first.
+}
+
+// NewFIFOScheduler creates an empty FIFOScheduler.
+func NewFIFOScheduler() *FIFOScheduler {
+	return &FIFOScheduler{}
+}
+
+// Push appends the work to the end of the queue.
+func (f *FIFOScheduler) Push(w Work) bool {
+	f.queue = append(f.queue, w)
+	return true
+}
+
+// Pop removes and returns the oldest pending work.
+func (f *FIFOScheduler) Pop() (Work, bool) {
+	if len(f.queue) == 0 {
+		return Work{}, false
+	}
+
+	w := f.queue[0]
+	f.queue[0] = Work{}
+	f.queue = f.queue[1:]
+	return w, true
+}
+
+// Len returns the number of pending work items.
+func (f *FIFOScheduler) Len() int {
+	return len(f.queue)
+}
+
+// LIFOScheduler executes the most recently discovered work first, which is a depth-first crawl.
+type LIFOScheduler struct {
+	stack []Work // Pending work, newest last.
+}
+
+// NewLIFOScheduler creates an empty LIFOScheduler.
+func NewLIFOScheduler() *LIFOScheduler {
+	return &LIFOScheduler{}
+}
+
+// Push adds the work on top of the stack.
+func (l *LIFOScheduler) Push(w Work) bool {
+	l.stack = append(l.stack, w)
+	return true
+}
+
+// Pop removes and returns the newest pending work.
+func (l *LIFOScheduler) Pop() (Work, bool) {
+	if len(l.stack) == 0 {
+		return Work{}, false
+	}
+
+	w := l.stack[len(l.stack)-1]
+	l.stack = l.stack[:len(l.stack)-1]
+	return w, true
+}
+
+// Len returns the number of pending work items.
+func (l *LIFOScheduler) Len() int {
+	return len(l.stack)
+}
+
+// URLScorer scores the URL of a work at the given depth, for a ScoredScheduler to execute the highest scores first.
+type URLScorer func(url string, depth int) float64
+
+// ScoredScheduler executes the pending work with the highest score first, for focused crawls preferring the URLs
+// most likely to yield valuable data. Each work is scored once, when pushed, by its URL and depth.
+// Work with equal scores is executed in the order it was discovered, so a constant score is a breadth-first crawl.
+type ScoredScheduler struct {
+	score URLScorer   // Scores the URLs of pushed work.
+	queue scoredQueue // Pending work, as a heap with the highest score on top.
+	seq   int         // Number of works pushed, breaking ties between equal scores.
+}
+
+// NewScoredScheduler creates an empty ScoredScheduler scoring work with score.
+func NewScoredScheduler(score URLScorer) *ScoredScheduler {
+	return &ScoredScheduler{score: score}
+}
+
+// Push scores the work and adds it to the queue.
+func (p *ScoredScheduler) Push(w Work) bool {
+	heap.Push(&p.queue, scoredWork{work: w, score: p.score(w.URL, w.Depth), seq: p.seq})
+	p.seq++
+	return true
+}
+
+// Pop removes and returns the pending work with the highest score, the oldest one among equal scores.
+func (p *ScoredScheduler) Pop() (Work, bool) {
+	if len(p.queue) == 0 {
+		return Work{}, false
+	}
+	return heap.Pop(&p.queue).(scoredWork).work, true
+}
+
+// Len returns the number of pending work items.
+func (p *ScoredScheduler) Len() int {
+	return len(p.queue)
+}
+
+// scoredWork is a work pending in a ScoredScheduler.
+type scoredWork struct {
+	work  Work    // Pending work.
+	score float64 // Score of the work.
+	seq   int     // Order in which the work was pushed.
+}
+
+// scoredQueue implements heap.Interface over scored work, with the highest score, then the lowest seq, on top.
+type scoredQueue []scoredWork
+
+func (q scoredQueue) Len() int { return len(q) }
+
+func (q scoredQueue) Less(i, j int) bool {
+	if q[i].score != q[j].score {
+		return q[i].score > q[j].score
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q scoredQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *scoredQueue) Push(x any) { *q = append(*q, x.(scoredWork)) }
+
+func (q *scoredQueue) Pop() any {
+	old := *q
+	w := old[len(old)-1]
+	old[len(old)-1] = scoredWork{}
+	*q = old[:len(old)-1]
+	return w
+}
+
+// WithScheduler sets the Scheduler deciding the order in which pages and item URLs are processed.
+// The default is a FIFOScheduler. A Scheduler holds the state of a single crawl and must not be shared between Scrapers.
+func WithScheduler(scheduler Scheduler) Option {
+	return func(o *options) {
+		if scheduler != nil {
+			o.scheduler = scheduler
+			o.urlScorer = nil
+		}
+	}
+}
+
+// WithURLScorer processes the pages and item URLs with the highest score first, as scored by score from their URL and
+// depth, such as to prefer URLs matching some path patterns or penalize deep ones. URLs with equal scores are processed
+// in the order they were discovered. It sets a ScoredScheduler, replacing the one of an earlier WithScheduler; a later
+// WithScheduler replaces it. Scores only order the pending work: work already in flight is not preempted.
+func WithURLScorer(score URLScorer) Option {
+	return func(o *options) {
+		if score != nil {
+			o.scheduler = NewScoredScheduler(score)
+			o.urlScorer = score
+		}
+	}
+}
+
+// schedule admits discovered work: it skips URLs that were already visited, waits for the discovery rate limit
+// and pushes the work to the scheduler. Pages are marked as visited when scheduled, so they are only discovered once.
+// The URL is normalized first when a URLNormalizer is configured, and dropped if it is on a host beyond WithMaxHosts
+// or beyond the MaxDepth or MaxPages of its strategy.
+func (s *Scraper[T]) schedule(ctx context.Context, w Work) error {
+	_, err := s.admit(ctx, w)
+	return err
+}
+
+// admit is like schedule, and also reports whether the work was pushed to the scheduler.
+func (s *Scraper[T]) admit(ctx context.Context, w Work) (bool, error) {
+	w.URL = s.canonical(w.URL)
+	if s.tooManyParams(w.URL) || !s.withinDepth(w) || !s.hosts.allow(hostKey(w.URL)) || s.scrapedUrls.Visited(s.visitKey(w)) {
+		return false, nil
+	}
+	if err := s.discovery.wait(ctx, ""); err != nil {
+		return false, err
+	}
+
+	// Pages beyond the limits of their strategy are dropped before being visited, so other strategies can still crawl them.
+	if w.Kind == PageWork {
+		if !s.takePage(w.Strategy) {
+			return false, nil
+		}
+		if !s.scrapedUrls.Visit(s.visitKey(w)) {
+			s.returnPage(w.Strategy)
+			return false, nil
+		}
+	}
+	return s.push(w)
+}
+
+// push reserves work in the frontier and offers it to the scheduler, dropping it when it is already pending or rejected.
+// It reports whether the work was accepted, and returns ErrDraining once the dispatcher has stopped.
+func (s *Scraper[T]) push(w Work) (bool, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return false, ErrDraining
+	}
+	if !s.reserve(w) {
+		s.mu.Unlock()
+		return false, nil
+	}
+	if w.Kind == PageWork {
+		w.seq = s.pages.page(w.Strategy)
+	}
+	accepted := s.scheduler.Push(w)
+	if accepted {
+		s.pending++
+		s.active[w.Strategy]++
+		if w.Kind == ItemWork {
+			s.pages.add(w.Strategy, w.seq)
+		}
+	} else {
+		s.untrack(w)
+		if w.Kind == PageWork {
+			s.pages.done(w.Strategy, w.seq)
+		}
+	}
+	s.mu.Unlock()
+
+	if accepted {
+		s.signal()
+	}
+	return accepted, nil
+}
+
+// next waits for the next work to execute.
+// It returns false once no work is pending and nothing is in flight, or when the context is done.
+func (s *Scraper[T]) next(ctx context.Context) (Work, bool) {
+	_, shared := s.scheduler.(Frontier)
+	drained := false
+	for {
+		s.mu.Lock()
+		if ctx.Err() != nil {
+			s.closed = true
+			s.mu.Unlock()
+			return Work{}, false
+		}
+
+		// Check for claims of other processes before popping, so work they push and acknowledge meanwhile is not missed.
+		busy := false
+		if f, ok := s.scheduler.(Frontier); ok {
+			busy = f.Claimed() > 0
+		}
+		w, ok := s.pop()
+		idle := !ok && s.pending == 0 && !busy
+
+		// A shared frontier must be found idle twice in a row, since another process may have visited a start page
+		// without having pushed it yet.
+		if shared && idle && !drained {
+			idle, drained = false, true
+		} else if !idle {
+			drained = false
+		}
+		if idle {
+			s.closed = true
+		}
+		s.mu.Unlock()
+
+		if ok {
+			return w, true
+		}
+		if idle {
+			return Work{}, false
+		}
+
+		// Wait for in-flight work to push new work or to finish, or for other processes to push work to a Frontier.
+		var poll <-chan time.Time
+		if shared {
+			poll = s.clock.After(frontierPoll)
+		}
+		select {
+		case <-s.wake:
+		case <-poll:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// ack acknowledges executed work to the scheduler when it is a Frontier.
+// Work is not acknowledged once the crawl is cancelled, since it may have been interrupted.
+func (s *Scraper[T]) ack(w Work) {
+	f, ok := s.scheduler.(Frontier)
+	if !ok || s.runCtx.Err() != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f.Ack(w)
+}
+
+// hold counts an operation that will push work later as pending, so the dispatcher keeps waiting for it.
+// It must be balanced by a call to releaseHold.
+func (s *Scraper[T]) hold() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending++
+	s.held++
+}
+
+// releaseHold marks an operation counted by hold as done.
+func (s *Scraper[T]) releaseHold() {
+	s.mu.Lock()
+	s.held--
+	s.mu.Unlock()
+
+	s.finish()
+}
+
+// finish marks a work item as done, once it has been executed.
+func (s *Scraper[T]) finish() {
+	s.mu.Lock()
+	s.pending--
+	s.mu.Unlock()
+
+	s.signal()
+}
+
+// signal wakes up the dispatcher waiting in next, if any.
+func (s *Scraper[T]) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// QueueDepth returns the number of pages and item URLs waiting in the scheduler, including paused ones. It is safe to call while the crawl is running.
+func (s *Scraper[T]) QueueDepth() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.scheduler.Len() + len(s.parked)
+}
+
+// InFlight returns the number of pages and item URLs currently being processed. It is safe to call while the crawl is running.
+func (s *Scraper[T]) InFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.inFlight()
+}
+
+// inFlight returns the number of work items popped from the scheduler and not finished yet, excluding the operations
+// counted by hold. The caller must hold s.mu.
+func (s *Scraper[T]) inFlight() int {
+	return s.pending - s.held - s.scheduler.Len() - len(s.parked)
+}
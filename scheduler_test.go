@@ -0,0 +1,107 @@
+package scrapify_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/ricardocastanho/scrapify"
+	"github.com/ricardocastanho/scrapify/scrapifytest"
+)
+
+func TestConcurrentDiscoveriesQueueURLsOnce(t *testing.T) {
+	const shared = 50
+
+	// Both branches return the same item URLs and next page at the same time.
+	var ready sync.WaitGroup
+	ready.Add(2)
+	branch := funcScraper[string]{
+		urls: func(ctx context.Context, url string) ([]string, []string, error) {
+			if url == "https://example.com/next" {
+				return nil, nil, nil
+			}
+			ready.Done()
+			ready.Wait()
+
+			items := make([]string, shared)
+			for i := range items {
+				items[i] = fmt.Sprintf("https://example.com/item/%d", i)
+			}
+			return items, []string{"https://example.com/next"}, nil
+		},
+		data: echo,
+	}
+	scheduler := scrapifytest.NewFakeScheduler()
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{
+		{Scraper: branch, Url: "https://example.com/a"},
+		{Scraper: branch, Url: "https://example.com/b"},
+	}, nil, 0, scrapify.WithScheduler(scheduler))
+
+	if err := scraper.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	pushed := make(map[string]int)
+	for _, w := range scheduler.Pushed() {
+		pushed[w.URL]++
+	}
+	for url, n := range pushed {
+		if n != 1 {
+			t.Errorf("%s queued %d times, want once", url, n)
+		}
+	}
+	if len(pushed) != shared+3 {
+		t.Errorf("%d distinct URLs queued, want %d", len(pushed), shared+3)
+	}
+	if got := scraper.Stats().URLs; got != shared {
+		t.Errorf("Stats().URLs = %d, want %d", got, shared)
+	}
+}
+
+func TestScoredSchedulerPopsHighestScoreFirst(t *testing.T) {
+	scores := map[string]float64{"/a": 1, "/b": 3, "/c": 2, "/d": 3, "/e": 1}
+	scheduler := scrapify.NewScoredScheduler(func(url string, depth int) float64 {
+		return scores[url] - float64(depth)
+	})
+	for _, url := range []string{"/a", "/b", "/c", "/d", "/e"} {
+		scheduler.Push(scrapify.Work{URL: url})
+	}
+	scheduler.Push(scrapify.Work{URL: "/b", Depth: 3})
+
+	var got []string
+	for scheduler.Len() > 0 {
+		w, _ := scheduler.Pop()
+		got = append(got, fmt.Sprintf("%s@%d", w.URL, w.Depth))
+	}
+	// Equal scores come out in push order.
+	want := []string{"/b@0", "/d@0", "/c@0", "/a@0", "/e@0", "/b@3"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("popped %v, want %v", got, want)
+	}
+	if _, ok := scheduler.Pop(); ok {
+		t.Error("Pop on an empty scheduler returned work")
+	}
+}
+
+func TestURLScorerScoresDiscoveredWork(t *testing.T) {
+	var mu sync.Mutex
+	depths := make(map[string]int)
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+		Scraper: funcScraper[string]{urls: listing(3), data: echo},
+		Url:     "https://example.com",
+	}}, nil, 0, scrapify.WithURLScorer(func(url string, depth int) float64 {
+		mu.Lock()
+		defer mu.Unlock()
+
+		depths[url] = depth
+		return 0
+	}))
+
+	if err := scraper.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	want := map[string]int{"https://example.com": 0, "https://example.com/item/0": 1, "https://example.com/item/1": 1, "https://example.com/item/2": 1}
+	if fmt.Sprint(depths) != fmt.Sprint(want) {
+		t.Errorf("scored %v, want %v", depths, want)
+	}
+}
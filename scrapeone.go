@@ -0,0 +1,63 @@
+package scrapify
+
+import (
+	"context"
+	"errors"
+)
+
+// ScrapeOne scrapes a single page synchronously with the scraper, without a Scraper nor any of its options: it calls
+// GetUrls on url, then GetData on each item URL discovered, one after the other, and returns the items they sent along
+// with the next pages discovered, to unit-test a scraper in isolation or debug it interactively.
+// Item URLs are not deduplicated and nothing is rate limited, retried or visited; the timeout of a URL discovered through
+// DiscoverURLs still applies to its call. Failures are returned as ScrapeErrors joined together, for the "get urls"
+// operation, which ends the call, or the "get data" operation of each failed item URL, with the items of the others.
+func ScrapeOne[T any](ctx context.Context, scraper IScraper[T], url string) ([]T, []string, error) {
+	urls, nextPages, err := discover(ctx, scraper, url)
+	if err != nil {
+		return nil, nil, &ScrapeError{Op: "get urls", URL: url, Err: err}
+	}
+
+	var items []T
+	var errs []error
+	for _, u := range urls {
+		got, err := scrapeItem(ctx, scraper, u)
+		items = append(items, got...)
+		if err != nil {
+			errs = append(errs, &ScrapeError{Op: "get data", URL: u.URL, Err: err})
+		}
+		if ctx.Err() != nil {
+			errs = append(errs, ctx.Err())
+			break
+		}
+	}
+
+	pages := make([]string, len(nextPages))
+	for i, p := range nextPages {
+		pages[i] = p.URL
+	}
+	return items, pages, errors.Join(errs...)
+}
+
+// scrapeItem calls GetData on the item URL and returns the items it sent.
+func scrapeItem[T any](ctx context.Context, scraper IScraper[T], u URL) ([]T, error) {
+	if u.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, u.Timeout)
+		defer cancel()
+	}
+
+	ch := make(chan T)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(ch)
+
+		var data T
+		errc <- scraper.GetData(ctx, ch, &data, u.URL)
+	}()
+
+	var items []T
+	for item := range ch {
+		items = append(items, item)
+	}
+	return items, <-errc
+}
@@ -0,0 +1,59 @@
+package scrapify_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+func TestScrapeOne(t *testing.T) {
+	scraper := funcScraper[string]{
+		urls: func(ctx context.Context, url string) ([]string, []string, error) {
+			return []string{url + "/a", url + "/b", url + "/broken"}, []string{url + "?page=2"}, nil
+		},
+		data: func(ctx context.Context, ch chan<- string, url string) error {
+			if strings.HasSuffix(url, "/broken") {
+				return errors.New("no price")
+			}
+			ch <- url + "#1"
+			ch <- url + "#2"
+			return nil
+		},
+	}
+
+	items, nextPages, err := scrapify.ScrapeOne(context.Background(), scraper, "https://example.com")
+	want := []string{"https://example.com/a#1", "https://example.com/a#2", "https://example.com/b#1", "https://example.com/b#2"}
+	if !slices.Equal(items, want) {
+		t.Errorf("items %v, want %v", items, want)
+	}
+	if !slices.Equal(nextPages, []string{"https://example.com?page=2"}) {
+		t.Errorf("next pages %v", nextPages)
+	}
+
+	var scrapeErr *scrapify.ScrapeError
+	if !errors.As(err, &scrapeErr) || scrapeErr.Op != "get data" || scrapeErr.URL != "https://example.com/broken" {
+		t.Errorf("error %v, want the get data failure of the broken item", err)
+	}
+}
+
+func TestScrapeOneDiscoveryFailure(t *testing.T) {
+	scraper := funcScraper[string]{
+		urls: func(ctx context.Context, url string) ([]string, []string, error) {
+			return nil, nil, errors.New("blocked")
+		},
+		data: echo,
+	}
+
+	items, nextPages, err := scrapify.ScrapeOne(context.Background(), scraper, "https://example.com")
+	var scrapeErr *scrapify.ScrapeError
+	if !errors.As(err, &scrapeErr) || scrapeErr.Op != "get urls" {
+		t.Errorf("error %v, want the get urls failure", err)
+	}
+	if items != nil || nextPages != nil {
+		t.Errorf("got items %v and next pages %v, want none", items, nextPages)
+	}
+}
@@ -2,7 +2,15 @@ package scrapify
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -10,140 +18,733 @@ import (
 // T is a generic type representing the data being scraped.
 type IScraper[T any] interface {
 	// GetUrls retrieves the URLs from the current page and the URLs of the next pages for pagination.
-	GetUrls(ctx context.Context, url string) ([]string, []string)
+	// When it returns an error, the page is considered failed and its URLs are ignored.
+	GetUrls(ctx context.Context, url string) ([]string, []string, error)
 
 	// GetData scrapes the data from a given URL and sends it to the provided channel.
-	GetData(ctx context.Context, ch chan<- T, data *T, url string)
+	GetData(ctx context.Context, ch chan<- T, data *T, url string) error
 }
 
 // Scraper represents the main structure that coordinates scraping jobs across multiple strategies.
 // It manages the scraping process, handles concurrency, and invokes a user-defined callback when data is scraped.
 type Scraper[T any] struct {
-	strategy     []ScraperStrategy[T] // A list of scraping strategies, each with a unique configuration.
-	jobs         chan ScraperJob[T]   // Channel that holds scraping jobs to be processed.
-	ch           chan T               // Channel through which scraped data is passed.
-	wg           sync.WaitGroup       // Synchronizes the goroutines to ensure proper job completion.
-	scrapedUrls  map[string]bool      // Tracks URLs that have already been scraped to avoid duplicates.
-	callback     func(T)              // User-provided callback function for processing scraped data.
-	requestDelay time.Duration        // User-defined delay between requests (default is 0, meaning no delay).
+	strategy       []ScraperStrategy[T]    // A list of scraping strategies, each with a unique configuration.
+	ch             chan delivery[T]        // Channel through which scraped data is passed.
+	wg             sync.WaitGroup          // Synchronizes the goroutines to ensure proper job completion.
+	scrapedUrls    VisitedStore            // Tracks URLs that have already been scraped to avoid duplicates.
+	callback       func(T)                 // User-provided callback function for processing scraped data (may be nil).
+	requestDelay   time.Duration           // User-defined delay between requests (default is 0, meaning no delay).
+	delays         []*rateLimiter          // Per-strategy request delays, indexed like strategy (nil when not overridden).
+	mu             sync.Mutex              // Guards the scheduler and the pending counter.
+	pending        int                     // Work pushed to the scheduler and not finished yet.
+	held           int                     // Operations counted in pending by hold, which are not work in flight.
+	closed         bool                    // Set once the dispatcher has stopped, after which no work is accepted.
+	pausedPages    bool                    // Whether discovery is paused, guarded by mu.
+	pausedItems    bool                    // Whether fetching is paused, guarded by mu.
+	parked         []Work                  // Work popped while its kind was paused, waiting to be resumed, guarded by mu.
+	wake           chan struct{}           // Wakes up the dispatcher when work is pushed or finished.
+	workers        chan struct{}           // Semaphore of free workers (nil when concurrency is unlimited).
+	adaptive       *adaptiveLimit          // Worker limit adjusted to the error rate (nil unless WithAdaptiveConcurrency is set).
+	frontier       map[string]Work         // Pending work, used for checkpointing.
+	frontierMu     sync.Mutex              // Guards the frontier map.
+	limiter        *rateLimiter            // Per-bucket rate limiter (nil when rate limiting is disabled).
+	depthLimiters  map[int]*rateLimiter    // Per-bucket rate limiters of the depths of WithDepthRateLimit, replacing limiter.
+	discovery      *rateLimiter            // Limits the rate at which discovered URLs are scheduled (nil when unlimited).
+	output         *rateLimiter            // Limits the rate at which items are delivered to the callback (nil when unlimited).
+	quotas         *quotaLimiter           // Per-host sliding-window quotas (nil when none is configured).
+	crawlDelays    *crawlDelays            // Per-origin crawl delays (nil when WithCrawlDelay is not set).
+	latency        *latencyDelays          // Per-host latency-aware delays (nil when WithLatencyAwareDelay is not set).
+	backoffs       *hostBackoffs           // Hosts backed off by a RetryAfterError.
+	hosts          *hostLimit              // Distinct hosts admitted with WithMaxHosts (nil when unlimited).
+	single         int                     // Index of the only strategy seeded, for RunStrategy (-1 seeds every strategy).
+	strategyStats  []*counters             // Progress counters of each strategy, indexed like strategy, see StatsByStrategy.
+	strategyPages  []atomic.Int64          // Pages claimed by each strategy against its MaxPages, indexed like strategy.
+	cancel         context.CancelCauseFunc // Cancels the crawl with the cause of the stop, see stop.
+	errs           []error                 // Errors returned by the scraper during the crawl.
+	errMu          sync.Mutex              // Guards the errs slice, errorHook and runErr.
+	runErr         error                   // Final error of the crawl, returned by Err.
+	stopReason     StopReason              // Why the crawl stopped, returned by StopReason.
+	itemHook       func(T)                 // Invoked with every delivered item before the callback, used by RunStream and RunAndCollect.
+	errorHook      func(error)             // Invoked with every recorded error, used by RunStream.
+	counters       counters                // Live progress counters, see Stats.
+	onItem         func(T, *CrawlHandle)   // Handler registered with OnItem (may be nil).
+	onItemMeta     func(T, ItemMeta)       // Handler registered with OnItemMeta (may be nil).
+	shouldContinue func(Stats, T) bool     // Predicate registered with WithShouldContinue (may be nil).
+	lastMu         sync.Mutex              // Guards lastItems.
+	lastItems      []T                     // Last item delivered for each strategy, kept for shouldContinue.
+	strategySlots  chan struct{}           // Semaphore bounding the number of active strategies (nil when unlimited).
+	active         []int                   // Number of pending or in-flight work items of each strategy, guarded by mu.
+	holding        []bool                  // Whether each strategy holds a slot of strategySlots, guarded by mu.
+	transform      func(T) (T, bool)       // Function registered with WithTransform (may be nil).
+	crashFlush     func([]T)               // Function registered with WithCrashFlush (may be nil).
+	crashOnce      sync.Once               // Ensures crashFlush is invoked once, by the first panicking goroutine.
+	itemKeys       *itemKeys[T]            // Keys of the delivered items, with WithItemDedup (nil otherwise).
+	pages          *pageOrder              // Pages of each strategy not done yet, with WithOrderedPagination (nil otherwise).
+	chains         itemChains              // Item URLs of the pages processed in order, with WithOrderedItems.
+	runCtx         context.Context         // Context of the running crawl, used by handles.
+	results        chan URLResult          // Channel returned by URLResults (nil when per-URL results are disabled).
+	resultsMu      sync.RWMutex            // Guards the closing of results against the sends of abandoned work.
+	resultsClosed  bool                    // Whether results is closed.
+	abandoned      chan struct{}           // Closed when in-flight work is abandoned, see WithDrainTimeout.
+	graph          *linkGraph              // Link graph of the crawl (nil unless recorded with WithGraphRecorder).
+	restored       *checkpoint             // State loaded with Restore, resumed by the next Run (nil if none).
+	optionErrs     []error                 // Options not matching the type of data of the Scraper, reported by validate.
+	options                                // Optional configuration set through Option functions.
 }
 
 // ScraperStrategy defines the strategy for scraping a specific URL with a given scraper implementation.
 // T represents the type of data being scraped.
 type ScraperStrategy[T any] struct {
-	Scraper IScraper[T] // The scraper implementation used to scrape data from the target URL.
-	Url     string      // The URL to start scraping from.
+	ID           string         // Identifies the strategy in errors, item metadata, URL results and RunStrategy (defaults to its index in the list given to NewScraper, as a decimal string).
+	Scraper      IScraper[T]    // The scraper implementation used to scrape data from the target URL.
+	Url          string         // The URL to start scraping from.
+	Request      *Request       // Optional request used to fetch the start URL, surfaced to the scraper through RequestFromContext.
+	SeedData     SeedDataMode   // Whether GetData is also called on the start URL (defaults to SeedLinksOnly).
+	RequestDelay *time.Duration // Optional delay between the item URLs of this strategy, overriding the one given to NewScraper.
+	MaxDepth     int            // Maximum depth of the pages and item URLs scheduled for this strategy, see Work.Depth (0 means unlimited).
+	MaxPages     int            // Maximum pages of this strategy passed to GetUrls, the start page included (0 means unlimited).
 }
 
-// ScraperJob represents a job containing the scraper and a list of URLs to process.
-// T is the type of data being scraped.
-type ScraperJob[T any] struct {
-	scraper IScraper[T] // The scraper instance used to perform the scraping.
-	urls    []string    // A list of URLs to be processed for scraping.
+// SeedDataMode controls whether the start URL of a strategy is scraped for data or only used to discover URLs.
+type SeedDataMode int
+
+const (
+	// SeedLinksOnly only calls GetUrls on the start URL, which is a listing page without data of its own.
+	// Its data is never scraped, even when GetUrls returns the start URL itself as an item URL. This is the default.
+	SeedLinksOnly SeedDataMode = iota
+	// SeedLinksAndData also calls GetData on the start URL, once GetUrls has succeeded, for start pages holding data.
+	SeedLinksAndData
+)
+
+// delivery is an item sent by a scraper, along with the work that produced it.
+type delivery[T any] struct {
+	item T        // The scraped item.
+	work Work     // The item URL whose GetData call sent the item.
+	meta ItemMeta // Provenance of the item, only set when OnItemMeta is.
 }
 
 // NewScraper creates a new Scraper instance.
-// logger is used for logging, s is the list of strategies to run, callback is the function that processes scraped data, and requestDelay is the optional delay between requests.
-func NewScraper[T any](s []ScraperStrategy[T], callback func(T), requestDelay time.Duration) *Scraper[T] {
-	return &Scraper[T]{
-		strategy:     s,
-		jobs:         make(chan ScraperJob[T]),
-		ch:           make(chan T),
-		scrapedUrls:  make(map[string]bool),
-		callback:     callback,
-		requestDelay: requestDelay, // Set the delay between requests.
+// s is the list of strategies to run, callback is the function that processes scraped data, requestDelay is the optional delay between requests, and opts are optional settings.
+// callback may be nil when items are consumed otherwise, with RunStream or OnItem for instance.
+func NewScraper[T any](s []ScraperStrategy[T], callback func(T), requestDelay time.Duration, opts ...Option) *Scraper[T] {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
 	}
+	return newScraper(s, callback, requestDelay, o)
 }
 
-// getData is responsible for processing jobs from the jobs channel and invoking the provided scraper.
-// It also ensures that the data is sent to the channel and the callback is called when the data is received.
-func (s *Scraper[T]) getData(ctx context.Context) {
+// newScraper creates a Scraper with the given configuration, see NewScraper.
+func newScraper[T any](s []ScraperStrategy[T], callback func(T), requestDelay time.Duration, o options) *Scraper[T] {
+	if o.visitedTTL > 0 {
+		o.visited = NewTTLVisitedStore(o.visitedTTL, o.clock)
+	}
+
+	// Trim the start URLs and normalize them like discovered URLs, without modifying the caller's strategies.
+	s = slices.Clone(s)
+	for i := range s {
+		s[i].Url = strings.TrimSpace(s[i].Url)
+		s[i].Url = o.canonical(s[i].Url)
+		if s[i].ID == "" {
+			s[i].ID = strconv.Itoa(i)
+		}
+	}
+
+	var workers chan struct{}
+	if o.concurrency > 0 {
+		workers = make(chan struct{}, o.concurrency)
+	}
+
+	var strategySlots chan struct{}
+	if o.maxStrategies > 0 {
+		strategySlots = make(chan struct{}, o.maxStrategies)
+	}
+
+	delays := make([]*rateLimiter, len(s))
+	for i, strategy := range s {
+		if strategy.RequestDelay != nil {
+			delays[i] = newIntervalLimiter(*strategy.RequestDelay, o.clock)
+		}
+	}
+
+	limiter := newRateLimiter(o.rateLimit, o.clock)
+	if o.sharedLimiter != nil {
+		limiter = o.sharedLimiter.limiter
+	}
+
+	// Buffer the scraped items as configured, or while the callback is paced.
+	buffer := o.resultBuffer
+	if buffer <= 0 && o.callbackRate > 0 {
+		buffer = callbackBuffer
+	}
+
+	var optionErrs []error
+	scraper := &Scraper[T]{
+		strategy:       s,
+		ch:             make(chan delivery[T], buffer),
+		abandoned:      make(chan struct{}),
+		scrapedUrls:    o.visited,
+		callback:       callback,
+		requestDelay:   requestDelay, // Set the delay between requests.
+		delays:         delays,
+		wake:           make(chan struct{}, 1),
+		workers:        workers,
+		adaptive:       newAdaptiveLimit(o),
+		frontier:       make(map[string]Work),
+		limiter:        limiter,
+		depthLimiters:  newDepthLimiters(o),
+		discovery:      newRateLimiter(o.discoveryRate, o.clock),
+		output:         newRateLimiter(o.callbackRate, o.clock),
+		quotas:         newQuotaLimiter(o),
+		crawlDelays:    newCrawlDelays(o),
+		latency:        newLatencyDelays(o),
+		backoffs:       newHostBackoffs(o.clock),
+		hosts:          newHostLimit(o, s),
+		graph:          newLinkGraph(o),
+		single:         -1,
+		strategyStats:  newStrategyCounters(len(s)),
+		strategyPages:  make([]atomic.Int64, len(s)),
+		onItem:         itemHandler[T](o, &optionErrs),
+		onItemMeta:     metaHandler[T](o, &optionErrs),
+		shouldContinue: continuePredicate[T](o, &optionErrs),
+		lastItems:      make([]T, len(s)),
+		strategySlots:  strategySlots,
+		active:         make([]int, len(s)),
+		holding:        make([]bool, len(s)),
+		transform:      transformer[T](o, &optionErrs),
+		crashFlush:     crashHandler[T](o, &optionErrs),
+		itemKeys:       newItemKeys[T](o, &optionErrs),
+		pages:          newPageOrder(o, len(s)),
+		chains:         newItemChains(o),
+		options:        o,
+	}
+	scraper.optionErrs = optionErrs
+	return scraper
+}
+
+// dispatch pops work from the scheduler and executes each item in its own goroutine until no work is left.
+// It stops dispatching new work as soon as the context is done.
+func (s *Scraper[T]) dispatch(ctx context.Context) {
+	for {
+		// Wait for a free worker when concurrency is limited, before popping the work, so the scheduler decides
+		// what runs next as late as possible and a shared Frontier leaves the work to other processes meanwhile.
+		if s.workers != nil {
+			select {
+			case s.workers <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if s.adaptive != nil {
+			if s.adaptive.acquire(ctx) != nil {
+				if s.workers != nil {
+					<-s.workers
+				}
+				return
+			}
+		}
+
+		w, ok := s.next(ctx)
+		if !ok {
+			if s.workers != nil {
+				<-s.workers
+			}
+			if s.adaptive != nil {
+				s.adaptive.release()
+			}
+			return
+		}
+
+		s.wg.Add(1)
+		go func(w Work) {
+			defer s.recoverCrash(nil)
+			defer s.wg.Done()
+			defer s.finish()
+			defer s.done(w)
+			defer s.ack(w)
+			defer s.pages.done(w.Strategy, w.seq)
+			if s.workers != nil {
+				defer func() { <-s.workers }()
+			}
+			if s.adaptive != nil {
+				defer s.adaptive.release()
+			}
+
+			if w.Kind == PageWork {
+				s.runScraper(ctx, w)
+			} else {
+				s.getData(ctx, w)
+			}
+		}(w)
+
+		// Apply the user-defined delay between requests, stopping early if the crawl is cancelled.
+		// Strategies overriding the delay are paced by their workers instead, so they do not slow down the others.
+		if w.Kind == ItemWork && s.requestDelay > 0 && s.strategy[w.Strategy].RequestDelay == nil {
+			select {
+			case <-s.clock.After(s.requestDelay):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// consume invokes the callback for every item received from the data channel.
+// The channel is drained until it is closed, even after cancellation, so in-flight scrapers never block on it,
+// or until in-flight work is abandoned with WithDrainTimeout.
+// The returned channel is closed once the last callback has returned.
+func (s *Scraper[T]) consume() <-chan struct{} {
+	done := make(chan struct{})
 	go func() {
-		for job := range s.jobs {
-			for _, url := range job.urls {
-				go func(url string) {
-					defer s.wg.Done()
-
-					// Skip already scraped URLs to avoid duplication.
-					if _, ok := s.scrapedUrls[url]; ok {
-						return
-					}
-
-					var data T
-					// Scrape the data from the URL and send it to the channel.
-					job.scraper.GetData(ctx, s.ch, &data, url)
-					s.scrapedUrls[url] = true
-
-				}(url)
-
-				// Apply the user-defined delay between requests.
-				if s.requestDelay > 0 {
-					time.Sleep(s.requestDelay)
+		defer close(done)
+
+		// Continuously process data from the channel and invoke the callback, until the channel is closed or in-flight work is abandoned.
+		// With WithOrderedPagination, items wait in the reorder buffer for the earlier pages of their strategy.
+		buf := newReorderBuffer[T](len(s.strategy))
+		defer s.recoverCrash(buf)
+		for {
+			var d delivery[T]
+			var ok bool
+			select {
+			case d, ok = <-s.ch:
+				if !ok {
+					s.flush(buf)
 				}
+			case <-s.pages.woken():
+				s.releaseAll(buf)
+				continue
+			case <-s.abandoned:
+			}
+			if !ok {
+				return
 			}
+			s.order(buf, d)
 		}
 	}()
+	return done
+}
 
-	go func() {
-		select {
-		case <-ctx.Done():
+// deliver transforms a scraped item and invokes the callback and the handlers with it, unless it is dropped.
+func (s *Scraper[T]) deliver(d delivery[T]) {
+	if s.transform != nil {
+		item, ok := s.transform(d.item)
+		if !ok {
+			s.counters.filtered.Add(1)
+			s.strategyStats[d.work.Strategy].filtered.Add(1)
 			return
-		default:
-			// Continuously process data from the channel and invoke the callback.
-			for data := range s.ch {
-				s.callback(data)
+		}
+		d.item = item
+	}
+	if !s.itemKeys.add(d.item) {
+		s.counters.duplicates.Add(1)
+		s.strategyStats[d.work.Strategy].duplicates.Add(1)
+		return
+	}
+
+	// Items scraped after the WithMaxItems budget was spent are dropped.
+	if s.itemsExhausted() {
+		return
+	}
+
+	// Pace the callbacks with WithCallbackRateLimit, until the crawl is cancelled.
+	_ = s.output.wait(s.runCtx, "")
+
+	if s.itemHook != nil {
+		s.itemHook(d.item)
+	}
+	if s.callback != nil {
+		s.invokeCallback(d)
+	}
+	if s.onItem != nil {
+		s.onItem(d.item, s.handle(s.runCtx, d.work))
+	}
+	if s.onItemMeta != nil {
+		s.onItemMeta(d.item, d.meta)
+	}
+	s.publishItem(d.work, d.item)
+	now := s.clock.Now()
+	s.counters.delivered(now)
+	s.strategyStats[d.work.Strategy].delivered(now)
+	s.remember(d.work.Strategy, d.item)
+	s.checkItems()
+}
+
+// invokeCallback invokes the callback with a delivered item, reporting the error of a sink failing to process it,
+// see NewJSONLinesSink. Other panics are propagated.
+func (s *Scraper[T]) invokeCallback(d delivery[T]) {
+	defer func() {
+		if r := recover(); r != nil {
+			failure, ok := r.(sinkFailure)
+			if !ok {
+				panic(r)
 			}
+			s.reportError(s.runCtx, "callback", d.work, failure.err)
 		}
 	}()
+	s.callback(d.item)
+}
+
+// getData scrapes the data of an item URL with the scraper of its strategy.
+// The scraper sends the data to the channel, from which the callback is invoked.
+func (s *Scraper[T]) getData(ctx context.Context, w Work) {
+	defer s.proceed(ctx, w)
+
+	// Skip already scraped URLs to avoid duplication.
+	if !s.scrapedUrls.Visit(s.visitKey(w)) {
+		s.untrack(w)
+		return
+	}
+
+	// Apply the delay of the strategy when it overrides the global one.
+	if err := s.delays[w.Strategy].wait(ctx, ""); err != nil {
+		return
+	}
+
+	s.scrape(ctx, w)
+
+	// Work interrupted by cancellation stays in the frontier so a checkpoint can resume it.
+	if ctx.Err() == nil {
+		s.untrack(w)
+	}
 }
 
-// runScraper starts the scraping process for a given strategy.
-// It handles both the retrieval of data URLs and pagination to new pages.
-func (s *Scraper[T]) runScraper(ctx context.Context, strategy ScraperStrategy[T]) {
-	defer s.wg.Done()
+// scrape calls GetData on the URL of the work, with retries, and reports its error.
+func (s *Scraper[T]) scrape(ctx context.Context, w Work) {
+	scraper := s.strategy[w.Strategy].Scraper
+
+	s.counters.urls.Add(1)
+	s.strategyStats[w.Strategy].urls.Add(1)
+	var last *recorder
+	attempts := 0
+	err := s.retry(ctx, w, func() error {
+		attempts++
+
+		// Wait for the rate limits and delays of the URL before its timeout starts, so waiting does not eat into it.
+		if err := s.throttle(ctx, scraper, w); err != nil {
+			return err
+		}
+
+		rctx, rec, cancel := s.requestContext(ctx, w)
+		defer cancel()
+		defer s.observe(w.URL, rec)
+		last = rec
+
+		// Scrape the data from the URL and forward it to the data channel.
+		return s.forward(w, rec, func(ch chan<- T) error {
+			var data T
+			return scraper.GetData(rctx, ch, &data, w.URL)
+		})
+	})
+	s.sendResult(w, last, attempts, 0, err)
+	if err != nil {
+		s.reportError(ctx, "get data", w, err)
+	}
+}
+
+// runScraper discovers the item URLs and next pages of a page.
+// Item URLs and next pages are pushed to the scheduler for further processing.
+func (s *Scraper[T]) runScraper(ctx context.Context, w Work) {
+	scraper := s.strategy[w.Strategy].Scraper
+
+	s.counters.pages.Add(1)
+	s.strategyStats[w.Strategy].pages.Add(1)
+	var urls, nextPages []URL
+	var last *recorder
+	attempts := 0
+	err := s.retry(ctx, w, func() error {
+		attempts++
+
+		// Wait for the rate limits and delays of the page before its timeout starts, so waiting does not eat into it.
+		if err := s.throttle(ctx, scraper, w); err != nil {
+			return err
+		}
+
+		rctx, rec, cancel := s.requestContext(ctx, w)
+		defer cancel()
+		defer s.observe(w.URL, rec)
+		last = rec
+
+		// Get URLs from the current page and the next pages for further scraping.
+		var err error
+		urls, nextPages, err = discover(rctx, scraper, w.URL)
+		return err
+	})
+	s.sendResult(w, last, attempts, len(urls)+len(nextPages), err)
+	if err != nil {
+		s.reportError(ctx, "get urls", w, err)
+		if ctx.Err() == nil {
+			s.untrack(w)
+		}
+		return
+	}
 
-	// Get URLs from the current page and the next pages for further scraping.
-	urls, nextPages := strategy.Scraper.GetUrls(ctx, strategy.Url)
-	s.scrapedUrls[strategy.Url] = true
-	s.wg.Add(len(urls))
-	s.wg.Add(1)
+	// Scrape the data of the start page itself when its strategy asks for it.
+	if strategy := s.strategy[w.Strategy]; strategy.SeedData == SeedLinksAndData && w.URL == strategy.Url {
+		s.scrape(ctx, Work{URL: w.URL, Strategy: w.Strategy, Kind: ItemWork, Depth: w.Depth, seq: w.seq})
+	}
 
-	// Send the URLs to the jobs channel for further processing.
-	s.jobs <- ScraperJob[T]{scraper: strategy.Scraper, urls: urls}
+	s.link(w.URL, urls, nextPages)
 
-	// Process the next pages recursively.
+	// Report dead ends, which are otherwise indistinguishable from a successful scrape.
+	if len(urls) == 0 && len(nextPages) == 0 {
+		s.counters.empty.Add(1)
+		s.strategyStats[w.Strategy].empty.Add(1)
+		if s.onEmpty != nil {
+			s.onEmpty(w.URL)
+		}
+	}
+
+	// Schedule the URLs for data scraping, keeping only the first ones with WithMaxURLsPerPage.
+	if s.maxURLsPerPage > 0 && len(urls) > s.maxURLsPerPage {
+		s.counters.truncated.Add(int64(len(urls) - s.maxURLsPerPage))
+		s.strategyStats[w.Strategy].truncated.Add(int64(len(urls) - s.maxURLsPerPage))
+		urls = urls[:s.maxURLsPerPage]
+	}
+	items := make([]Work, len(urls))
+	for i, url := range urls {
+		items[i] = Work{URL: url.URL, Strategy: w.Strategy, Kind: ItemWork, Timeout: url.Timeout, Depth: w.Depth + 1, seq: w.seq}
+	}
+	ordered := s.orderItems()
+	if !ordered {
+		for _, item := range items {
+			if err := s.schedule(ctx, item); err != nil {
+				return
+			}
+		}
+	}
+
+	// Schedule the next pages for discovery, unless the ShouldContinue predicate stops pagination.
+	if s.noPagination || len(nextPages) > 0 && !s.following(w.Strategy) {
+		nextPages = nil
+	}
+	nextPages = s.paginate(w.URL, nextPages)
 	for _, newUrl := range nextPages {
-		if _, ok := s.scrapedUrls[newUrl]; ok {
+		if err := s.schedule(ctx, Work{URL: newUrl.URL, Strategy: w.Strategy, Kind: PageWork, Timeout: newUrl.Timeout, Depth: w.Depth + 1}); err != nil {
+			return
+		}
+	}
+
+	// With WithOrderedItems, the item URLs are scheduled one by one, and the last one untracks the page once done.
+	if ordered {
+		chained, err := s.chain(ctx, w, items)
+		if chained || err != nil {
+			return
+		}
+	}
+	if ctx.Err() == nil {
+		s.untrack(w)
+	}
+}
+
+// forward runs a GetData call with its own item channel and forwards every item to the data channel,
+// tagged with the work that produced it and, when OnItemMeta is set, with its provenance.
+func (s *Scraper[T]) forward(w Work, rec *recorder, get func(ch chan<- T) error) error {
+	items := make(chan T)
+	errc := make(chan error, 1)
+
+	go func() {
+		defer s.recoverCrash(nil)
+		defer close(items)
+		errc <- get(items)
+	}()
+
+	for item := range items {
+		if rec.duplicated() {
+			s.counters.duplicates.Add(1)
+			s.strategyStats[w.Strategy].duplicates.Add(1)
 			continue
 		}
+		rec.count()
+		d := delivery[T]{item: item, work: w}
+		if s.onItemMeta != nil {
+			d.meta = s.meta(w, rec)
+		}
+		// Items of abandoned work are dropped, the data channel no longer being drained.
+		s.pages.add(w.Strategy, w.seq)
+		select {
+		case s.ch <- d:
+		case <-s.abandoned:
+		}
+	}
+	return <-errc
+}
 
-		s.wg.Add(1)
-		s.scrapedUrls[newUrl] = true
+// validate checks that every strategy has a scraper and a valid start URL, and reports all the invalid ones,
+// along with the options that do not match the type of data of the Scraper.
+func (s *Scraper[T]) validate() error {
+	errs := slices.Clone(s.optionErrs)
+	ids := make(map[string]bool, len(s.strategy))
+	for i, strategy := range s.strategy {
+		if ids[strategy.ID] {
+			errs = append(errs, fmt.Errorf("scrapify: strategy %d: duplicate ID %q", i, strategy.ID))
+		}
+		ids[strategy.ID] = true
+		if strategy.Scraper == nil {
+			errs = append(errs, fmt.Errorf("scrapify: strategy %d: nil Scraper", i))
+		}
+		if strategy.Url == "" {
+			errs = append(errs, fmt.Errorf("scrapify: strategy %d: empty Url", i))
+		} else if _, err := url.Parse(strategy.Url); err != nil {
+			errs = append(errs, fmt.Errorf("scrapify: strategy %d: invalid Url: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
 
-		// Recursively call runScraper to handle pagination.
-		go s.runScraper(ctx, ScraperStrategy[T]{Scraper: strategy.Scraper, Url: newUrl})
+// seed schedules the start page of each strategy.
+// Start pages are marked as visited when scheduled, like discovered pages, so a start page shared by several strategies
+// or linked back to by its own pagination is only passed to GetUrls once.
+// With a startup stagger or a limit of concurrent strategies, pages are scheduled in the background,
+// with a random delay between them or as soon as a strategy slot is free.
+func (s *Scraper[T]) seed(ctx context.Context) {
+	if s.startupStagger <= 0 && s.strategySlots == nil {
+		for i, strategy := range s.strategy {
+			if s.seeds(i) {
+				s.seedPage(i, strategy.Url)
+			}
+		}
+		return
+	}
+
+	s.hold()
+	go func() {
+		defer s.releaseHold()
+
+		for i, strategy := range s.strategy {
+			if !s.seeds(i) {
+				continue
+			}
+			if i > 0 && s.startupStagger > 0 {
+				select {
+				case <-s.clock.After(rand.N(s.startupStagger)):
+				case <-ctx.Done():
+					return
+				}
+			}
+			if !s.acquireStrategy(ctx, i) {
+				return
+			}
+			s.seedPage(i, strategy.Url)
+
+			// Free the slot right away when the start page was not scheduled.
+			s.releaseStrategy(i)
+		}
+	}()
+}
+
+// seedPage schedules the start page of a strategy unless it has already been visited.
+func (s *Scraper[T]) seedPage(strategy int, url string) {
+	w := Work{URL: url, Strategy: strategy, Kind: PageWork}
+	if !s.takePage(strategy) {
+		return
+	}
+	if s.scrapedUrls.Visit(s.visitKey(w)) {
+		s.push(w)
+	} else {
+		s.returnPage(strategy)
 	}
 }
 
 // Run starts the entire scraping process by running each strategy and managing concurrency.
 // It waits for all scraping jobs to complete before closing the channels.
-func (s *Scraper[T]) Run(ctx context.Context) {
-	// Add all strategies to the wait group.
-	s.wg.Add(len(s.strategy))
+// It returns the errors reported by the scrapers, joined together, or only the first one with WithFirstErrorStops.
+// When checkpointing is enabled, it resumes from an existing checkpoint and also returns any error loading or writing it.
+// Strategies are validated first: if any has a nil Scraper or an empty or unparseable Url, Run returns an error naming each of them without crawling.
+// So are the options taking a function of the items, such as OnItem, which must match the type of data of the Scraper.
+// A context already done when Run is called makes it return the context error right away, without crawling.
+// Only then is the context error returned: a context done during the crawl stops it, and Run returns the errors
+// recorded until then, or nil if none.
+// Once it returns, StopReason tells whether the crawl completed or why it was stopped.
+func (s *Scraper[T]) Run(ctx context.Context) error {
+	<-s.Start(ctx)
+	return s.Err()
+}
+
+// Start launches the scraping process in the background and returns a channel closed once it has completed.
+// While the crawl runs, its progress can be observed with Stats, QueueDepth and InFlight.
+// Once the channel is closed, Err returns the error Run would have returned.
+func (s *Scraper[T]) Start(ctx context.Context) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer s.recoverCrash(nil)
+		defer close(done)
+
+		reason, err := s.run(ctx)
 
-	// Start processing jobs and data.
-	s.getData(ctx)
+		s.errMu.Lock()
+		s.runErr = err
+		s.stopReason = reason
+		s.errMu.Unlock()
+	}()
+	return done
+}
+
+// Err returns the error of a crawl launched with Start, once its done channel has been closed.
+func (s *Scraper[T]) Err() error {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+
+	return s.runErr
+}
+
+// run executes the crawl and returns the reason it stopped and its error, see Run.
+func (s *Scraper[T]) run(ctx context.Context) (StopReason, error) {
+	s.start()
+	defer s.complete()
+	defer s.closeResults()
+
+	if err := s.validate(); err != nil {
+		return "", err
+	}
+
+	// A context done before the crawl starts fails it right away, without loading the checkpoint nor scheduling anything.
+	if err := ctx.Err(); err != nil {
+		return stopReason(ctx), err
+	}
 
-	// Run each scraping strategy in a separate goroutine.
-	for i := range s.strategy {
-		strategy := s.strategy[i]
-		go s.runScraper(ctx, strategy)
+	cp, err := s.loadCheckpoint()
+	if err != nil {
+		return "", err
 	}
+	if s.restored != nil {
+		cp, s.restored = s.restored, nil
+	}
+
+	// The crawl runs under its own context so it can be stopped internally, with the cause of the stop.
+	ctx, s.cancel = context.WithCancelCause(ctx)
+	defer s.cancel(nil)
+	s.runCtx = ctx
+
+	// Serve the profiles, if enabled, for the duration of the crawl.
+	stopProfiling, err := s.startProfiling()
+	if err != nil {
+		return "", err
+	}
+	defer stopProfiling()
+
+	// Open the connections to the hosts of the start pages, if enabled, before the first requests need them.
+	s.prewarm(ctx)
 
-	// Wait for all jobs to complete.
-	s.wg.Wait()
+	// Start processing data.
+	consumed := s.consume()
+	stopCheckpointing := s.startCheckpointing()
+
+	// Resume the crawl from the saved frontier, if any, and schedule the start page of each strategy.
+	if cp != nil {
+		s.resume(cp)
+	}
+	s.seed(ctx)
+
+	// Execute the scheduled work and wait for all of it to complete.
+	s.dispatch(ctx)
+	abandoned := s.drain(ctx)
+	stopCheckpointing()
+
+	// Close the channel after all work is done, unless some was abandoned and may still send, and wait for the last callback.
+	if !abandoned {
+		close(s.ch)
+	}
+	<-consumed
 
-	// Close the channels after all work is done.
-	close(s.jobs)
-	close(s.ch)
+	return stopReason(ctx), errors.Join(s.err(), s.finishCheckpoint(ctx))
 }
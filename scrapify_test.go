@@ -0,0 +1,244 @@
+package scrapify_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+// funcScraper is an IScraper delegating to functions, so each test declares the site it crawls inline.
+type funcScraper[T any] struct {
+	urls func(ctx context.Context, url string) ([]string, []string, error)
+	data func(ctx context.Context, ch chan<- T, url string) error
+}
+
+// GetUrls calls urls, discovering nothing when it is nil.
+func (f funcScraper[T]) GetUrls(ctx context.Context, url string) ([]string, []string, error) {
+	if f.urls == nil {
+		return nil, nil, nil
+	}
+	return f.urls(ctx, url)
+}
+
+// GetData calls data, sending nothing when it is nil.
+func (f funcScraper[T]) GetData(ctx context.Context, ch chan<- T, data *T, url string) error {
+	if f.data == nil {
+		return nil
+	}
+	return f.data(ctx, ch, url)
+}
+
+// listing returns a GetUrls function discovering n item URLs under every page, named after the page, and no next page.
+func listing(n int) func(ctx context.Context, url string) ([]string, []string, error) {
+	return func(ctx context.Context, url string) ([]string, []string, error) {
+		items := make([]string, n)
+		for i := range items {
+			items[i] = fmt.Sprintf("%s/item/%d", url, i)
+		}
+		return items, nil, nil
+	}
+}
+
+// echo is a GetData function sending the URL it is called on.
+func echo(ctx context.Context, ch chan<- string, url string) error {
+	ch <- url
+	return nil
+}
+
+// collector accumulates the items of a crawl, safe for concurrent use.
+type collector[T any] struct {
+	mu    sync.Mutex
+	items []T
+}
+
+// add appends the item, with the signature of a callback.
+func (c *collector[T]) add(item T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = append(c.items, item)
+}
+
+// result returns the items collected so far.
+func (c *collector[T]) result() []T {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]T(nil), c.items...)
+}
+
+func TestCancelDuringRequestDelayReturnsPromptly(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var got collector[string]
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+		Scraper: funcScraper[string]{urls: listing(3), data: echo},
+		Url:     "https://example.com/list",
+	}}, func(item string) {
+		got.add(item)
+		cancel()
+	}, time.Hour)
+
+	start := time.Now()
+	if err := scraper.Run(ctx); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Run returned %v after the cancellation, want it to stop waiting for the delay", elapsed)
+	}
+	if n := len(got.result()); n != 1 {
+		t.Errorf("%d items delivered, want only the one before the delay", n)
+	}
+	if reason := scraper.StopReason(); reason != scrapify.StopCancelled {
+		t.Errorf("StopReason = %q, want %q", reason, scrapify.StopCancelled)
+	}
+}
+
+func TestRunWithDoneContextReturnsImmediately(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		err    error
+		reason scrapify.StopReason
+	}{
+		{"cancelled", cancelled, context.Canceled, scrapify.StopCancelled},
+		{"deadline exceeded", expired, context.DeadlineExceeded, scrapify.StopDeadline},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int64
+			scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+				Scraper: funcScraper[string]{urls: func(ctx context.Context, url string) ([]string, []string, error) {
+					calls.Add(1)
+					return nil, nil, nil
+				}},
+				Url: "https://example.com/list",
+			}}, nil, time.Hour)
+
+			start := time.Now()
+			if err := scraper.Run(tt.ctx); !errors.Is(err, tt.err) {
+				t.Errorf("Run error %v, want %v", err, tt.err)
+			}
+			if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+				t.Errorf("Run returned after %v, want immediately", elapsed)
+			}
+			if n := calls.Load(); n != 0 {
+				t.Errorf("GetUrls called %d times, want no work started", n)
+			}
+			if reason := scraper.StopReason(); reason != tt.reason {
+				t.Errorf("StopReason = %q, want %q", reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestRunWithoutCallback(t *testing.T) {
+	var handled collector[string]
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+		Scraper: funcScraper[string]{urls: listing(3), data: echo},
+		Url:     "https://example.com/list",
+	}}, nil, 0, scrapify.OnItem(func(item string, _ *scrapify.CrawlHandle) { handled.add(item) }))
+
+	if err := scraper.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got := len(handled.result()); got != 3 {
+		t.Errorf("OnItem received %d items, want 3", got)
+	}
+	if got := scraper.Stats().Items; got != 3 {
+		t.Errorf("Stats().Items = %d, want 3", got)
+	}
+}
+
+func TestRunDoneDuringCrawlReturnsRecordedErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		fail   bool
+		reason scrapify.StopReason
+	}{
+		{"cancelled", false, scrapify.StopCancelled},
+		{"deadline exceeded", false, scrapify.StopDeadline},
+		{"cancelled after an error", true, scrapify.StopCancelled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.reason == scrapify.StopDeadline {
+				ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+			}
+			defer cancel()
+
+			scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+				Scraper: funcScraper[string]{urls: listing(1), data: func(ctx context.Context, ch chan<- string, url string) error {
+					if tt.fail {
+						defer cancel()
+						return errors.New("broken item")
+					}
+					if tt.reason == scrapify.StopCancelled {
+						cancel()
+					}
+					<-ctx.Done()
+					return ctx.Err()
+				}},
+				Url: "https://example.com/list",
+			}}, nil, 0)
+
+			err := scraper.Run(ctx)
+			if tt.fail && (err == nil || errors.Is(err, context.Canceled)) {
+				t.Errorf("Run error %v, want only the error of the broken item", err)
+			}
+			if !tt.fail && err != nil {
+				t.Errorf("Run error %v, want nil for a crawl stopped by its context", err)
+			}
+			if reason := scraper.StopReason(); reason != tt.reason {
+				t.Errorf("StopReason = %q, want %q", reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestPaginationCyclesDiscoverEachPageOnce(t *testing.T) {
+	// The start page links to two pages both linking to a third one, which links back to the start page and to itself.
+	next := map[string][]string{
+		"https://example.com/1": {"https://example.com/2", "https://example.com/3"},
+		"https://example.com/2": {"https://example.com/4"},
+		"https://example.com/3": {"https://example.com/4"},
+		"https://example.com/4": {"https://example.com/1", "https://example.com/4"},
+	}
+
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+		Scraper: funcScraper[string]{urls: func(ctx context.Context, url string) ([]string, []string, error) {
+			mu.Lock()
+			calls[url]++
+			mu.Unlock()
+			return nil, next[url], nil
+		}},
+		Url: "https://example.com/1",
+	}}, nil, 0, scrapify.WithConcurrency(4))
+
+	if err := scraper.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	for url := range next {
+		if calls[url] != 1 {
+			t.Errorf("GetUrls called %d times on %s, want once", calls[url], url)
+		}
+	}
+	if got := scraper.Stats().Pages; got != 4 {
+		t.Errorf("Stats().Pages = %d, want 4", got)
+	}
+}
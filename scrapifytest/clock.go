@@ -0,0 +1,87 @@
+// Package scrapifytest provides test doubles for the scrapify package.
+package scrapifytest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+// FakeClock is a scrapify.Clock whose time only moves when Advance is called.
+// It lets tests verify delays, rate limits and timeouts deterministically and without waiting.
+// It is safe for concurrent use.
+type FakeClock struct {
+	mu      sync.Mutex // Guards the fields below.
+	now     time.Time  // Current fake time.
+	waiters []waiter   // Pending timers, fired by Advance.
+}
+
+// waiter is a timer created by After or Sleep.
+type waiter struct {
+	deadline time.Time      // Time at which the timer fires.
+	ch       chan time.Time // Receives the fake time when the timer fires.
+}
+
+// NewFakeClock creates a FakeClock set to the given time.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the current fake time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// After returns a channel receiving the fake time once the clock has been advanced by at least d.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+
+	c.waiters = append(c.waiters, waiter{deadline: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Sleep blocks until the clock has been advanced by at least d.
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// Advance moves the clock forward by d and fires every timer whose deadline has been reached.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.deadline.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+// Waiters returns the number of timers waiting for the clock to advance.
+// Tests can poll it to know when the code under test is blocked on the clock.
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.waiters)
+}
+
+// Ensure FakeClock satisfies the scrapify.Clock interface.
+var _ scrapify.Clock = (*FakeClock)(nil)
@@ -0,0 +1,58 @@
+package scrapifytest
+
+import (
+	"context"
+
+	"github.com/ricardocastanho/scrapify"
+	"github.com/ricardocastanho/scrapify/fetch"
+)
+
+// ReplayScraper implements scrapify.IScraper by serving the responses archived with fetch.WithResponseArchive
+// instead of fetching them, so parsing logic can be run deterministically and offline, in tests or CI.
+// Extract the parsing of the live scraper into functions of a *fetch.Response and use them for both:
+//
+//	replay := &scrapifytest.ReplayScraper[Product]{Dir: "testdata/archive", URLs: parseListing, Data: parseProduct}
+//	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[Product]{{Scraper: replay, Url: startURL}}, callback, 0)
+//
+// Discovery and pagination are reproduced as long as every URL they reach was archived.
+// A URL missing from the archive fails with an error wrapping fs.ErrNotExist.
+type ReplayScraper[T any] struct {
+	Dir  string                                                                    // Archive directory given to fetch.WithResponseArchive.
+	URLs func(resp *fetch.Response) (urls []string, nextPages []string, err error) // Extracts the item URLs and next pages of a listing page.
+	Data func(resp *fetch.Response) ([]T, error)                                   // Extracts the items of an item page.
+}
+
+// GetUrls loads the archived listing page and extracts its item URLs and next pages with URLs.
+func (r *ReplayScraper[T]) GetUrls(ctx context.Context, url string) ([]string, []string, error) {
+	resp, err := fetch.LoadArchived(r.Dir, url)
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.URLs == nil {
+		return nil, nil, nil
+	}
+	return r.URLs(resp)
+}
+
+// GetData loads the archived item page, extracts its items with Data and sends each of them to the channel.
+func (r *ReplayScraper[T]) GetData(ctx context.Context, ch chan<- T, data *T, url string) error {
+	resp, err := fetch.LoadArchived(r.Dir, url)
+	if err != nil {
+		return err
+	}
+	if r.Data == nil {
+		return nil
+	}
+
+	items, err := r.Data(resp)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		*data = item
+		ch <- *data
+	}
+	return nil
+}
+
+var _ scrapify.IScraper[struct{}] = (*ReplayScraper[struct{}])(nil)
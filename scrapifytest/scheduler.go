@@ -0,0 +1,79 @@
+package scrapifytest
+
+import (
+	"sync"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+// FakeScheduler is a first-in, first-out scrapify.Scheduler recording the work pushed to it and popped from it,
+// to assert what was enqueued and in which order it ran, such as no page beyond some depth being ever enqueued.
+// Pass it to the Scraper with scrapify.WithScheduler. Its Reject function, if set, is called with every pushed work
+// and rejects the work it returns true for, to test admission. It is safe for concurrent use.
+type FakeScheduler struct {
+	Reject func(w scrapify.Work) bool // Rejects the pushed work it returns true for (may be nil).
+
+	mu      sync.Mutex      // Guards the fields below.
+	pending []scrapify.Work // Work waiting to be popped, oldest first.
+	pushed  []scrapify.Work // Every work offered to Push, accepted or not.
+	popped  []scrapify.Work // Every work returned by Pop.
+}
+
+// NewFakeScheduler creates an empty FakeScheduler.
+func NewFakeScheduler() *FakeScheduler {
+	return &FakeScheduler{}
+}
+
+// Push records the work and appends it to the pending work, unless Reject rejects it.
+func (s *FakeScheduler) Push(w scrapify.Work) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pushed = append(s.pushed, w)
+	if s.Reject != nil && s.Reject(w) {
+		return false
+	}
+	s.pending = append(s.pending, w)
+	return true
+}
+
+// Pop removes, records and returns the oldest pending work.
+func (s *FakeScheduler) Pop() (scrapify.Work, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.pending) == 0 {
+		return scrapify.Work{}, false
+	}
+	w := s.pending[0]
+	s.pending = s.pending[1:]
+	s.popped = append(s.popped, w)
+	return w, true
+}
+
+// Len returns the number of pending work items.
+func (s *FakeScheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.pending)
+}
+
+// Pushed returns every work offered to Push, accepted or rejected, in the order it was pushed.
+func (s *FakeScheduler) Pushed() []scrapify.Work {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]scrapify.Work(nil), s.pushed...)
+}
+
+// Popped returns every work returned by Pop, in the order it was dispatched.
+func (s *FakeScheduler) Popped() []scrapify.Work {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]scrapify.Work(nil), s.popped...)
+}
+
+// Ensure FakeScheduler satisfies the scrapify.Scheduler interface.
+var _ scrapify.Scheduler = (*FakeScheduler)(nil)
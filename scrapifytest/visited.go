@@ -0,0 +1,67 @@
+package scrapifytest
+
+import (
+	"sync"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+// FakeVisitedStore is an in-memory scrapify.ListableVisitedStore recording how many times each URL was visited,
+// to assert deduplication, such as a URL being visited exactly once. Pass it to the Scraper with scrapify.WithVisitedStore.
+// It is safe for concurrent use.
+type FakeVisitedStore struct {
+	mu     sync.Mutex     // Guards the fields below.
+	visits map[string]int // Number of Visit calls of each URL.
+	order  []string       // Visited URLs, in the order they were first visited.
+}
+
+// NewFakeVisitedStore creates an empty FakeVisitedStore, optionally with URLs already visited once.
+func NewFakeVisitedStore(urls ...string) *FakeVisitedStore {
+	s := &FakeVisitedStore{visits: make(map[string]int)}
+	for _, url := range urls {
+		s.Visit(url)
+	}
+	return s
+}
+
+// Visit marks the URL as visited, counts the visit and reports whether the URL was newly added.
+func (s *FakeVisitedStore) Visit(url string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.visits[url]++
+	if s.visits[url] > 1 {
+		return false
+	}
+	s.order = append(s.order, url)
+	return true
+}
+
+// Visited reports whether the URL has already been visited.
+func (s *FakeVisitedStore) Visited(url string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.visits[url] > 0
+}
+
+// URLs returns the visited URLs, in the order they were first visited.
+func (s *FakeVisitedStore) URLs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.order...)
+}
+
+// Visits returns the number of times Visit was called with the URL, including the calls made once it was visited.
+// The Scraper visits pages when scheduling them and item URLs when scraping them, so a URL discovered several times
+// is visited several times but only scraped once.
+func (s *FakeVisitedStore) Visits(url string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.visits[url]
+}
+
+// Ensure FakeVisitedStore satisfies the scrapify.ListableVisitedStore interface.
+var _ scrapify.ListableVisitedStore = (*FakeVisitedStore)(nil)
@@ -0,0 +1,124 @@
+package scrapify
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// CountingSink counts the items of a crawl. Pass its Callback method to NewScraper:
+//
+//	sink := scrapify.NewCountingSink[Product]()
+//	scraper := scrapify.NewScraper(strategies, sink.Callback, 0)
+//
+// It is safe for concurrent use.
+type CountingSink[T any] struct {
+	n atomic.Int64 // Number of items received.
+}
+
+// NewCountingSink creates a CountingSink with a count of zero.
+func NewCountingSink[T any]() *CountingSink[T] {
+	return &CountingSink[T]{}
+}
+
+// Callback counts the item. It has the signature of the callback expected by NewScraper.
+func (c *CountingSink[T]) Callback(item T) {
+	c.n.Add(1)
+}
+
+// Result returns the number of items received so far.
+func (c *CountingSink[T]) Result() int64 {
+	return c.n.Load()
+}
+
+// CollectingSink keeps every item of a crawl, in the order they were received. It is safe for concurrent use.
+type CollectingSink[T any] struct {
+	mu    sync.Mutex // Guards items.
+	items []T        // Items received so far.
+}
+
+// NewCollectingSink creates an empty CollectingSink.
+func NewCollectingSink[T any]() *CollectingSink[T] {
+	return &CollectingSink[T]{}
+}
+
+// Callback appends the item. It has the signature of the callback expected by NewScraper.
+func (c *CollectingSink[T]) Callback(item T) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = append(c.items, item)
+}
+
+// Result returns a copy of the items received so far.
+func (c *CollectingSink[T]) Result() []T {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return slices.Clone(c.items)
+}
+
+// GroupBySink groups the items of a crawl by the key returned by a function. It is safe for concurrent use.
+type GroupBySink[K comparable, T any] struct {
+	key    func(T) K  // Computes the group of an item.
+	mu     sync.Mutex // Guards groups.
+	groups map[K][]T  // Items received so far, by group.
+}
+
+// NewGroupBySink creates an empty GroupBySink grouping items by the result of key.
+func NewGroupBySink[K comparable, T any](key func(T) K) *GroupBySink[K, T] {
+	return &GroupBySink[K, T]{key: key, groups: make(map[K][]T)}
+}
+
+// Callback adds the item to its group. It has the signature of the callback expected by NewScraper.
+func (g *GroupBySink[K, T]) Callback(item T) {
+	k := g.key(item)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.groups[k] = append(g.groups[k], item)
+}
+
+// Result returns a copy of the groups received so far, each in the order its items were received.
+func (g *GroupBySink[K, T]) Result() map[K][]T {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	groups := maps.Clone(g.groups)
+	for k, items := range groups {
+		groups[k] = slices.Clone(items)
+	}
+	return groups
+}
+
+// NewJSONLinesSink returns a callback writing every item to w as a line of JSON, to pipe the output of a crawl
+// into jq or another process. Writes are serialized, so lines never interleave.
+// Items that cannot be marshaled or written are reported like the errors of the scrapers, as a ScrapeError with
+// the "callback" operation passed to OnError and returned by Run. The callback must thus be invoked by a Scraper,
+// as the callback given to NewScraper or from it; called elsewhere, it panics with these errors.
+func NewJSONLinesSink[T any](w io.Writer) func(T) {
+	var mu sync.Mutex
+
+	return func(item T) {
+		line, err := json.Marshal(item)
+		if err == nil {
+			mu.Lock()
+			_, err = w.Write(append(line, '\n'))
+			mu.Unlock()
+		}
+		if err != nil {
+			panic(sinkFailure{fmt.Errorf("scrapify: writing JSON line: %w", err)})
+		}
+	}
+}
+
+// sinkFailure is the panic value with which a sink of this package reports the error of an item to the Scraper
+// invoking it.
+type sinkFailure struct {
+	err error
+}
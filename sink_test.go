@@ -0,0 +1,54 @@
+package scrapify_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+// failingWriter writes to buf, failing the writes containing fail.
+type failingWriter struct {
+	buf  bytes.Buffer
+	fail string
+}
+
+// Write fails if p contains fail.
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if strings.Contains(string(p), w.fail) {
+		return 0, errors.New("disk full")
+	}
+	return w.buf.Write(p)
+}
+
+func TestJSONLinesSinkReportsWriteErrors(t *testing.T) {
+	w := &failingWriter{fail: "item/1"}
+	var reported collector[error]
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+		Scraper: funcScraper[string]{urls: listing(3), data: echo},
+		Url:     "https://example.com/list",
+	}}, scrapify.NewJSONLinesSink[string](w), 0, scrapify.OnError(reported.add))
+
+	err := scraper.Run(context.Background())
+	var scrapeErr *scrapify.ScrapeError
+	if !errors.As(err, &scrapeErr) {
+		t.Fatalf("Run: %v, want a ScrapeError", err)
+	}
+	if scrapeErr.Op != "callback" || scrapeErr.URL != "https://example.com/list/item/1" {
+		t.Errorf("ScrapeError = %q on %s, want callback on https://example.com/list/item/1", scrapeErr.Op, scrapeErr.URL)
+	}
+	if got := len(reported.result()); got != 1 {
+		t.Errorf("OnError received %d errors, want 1", got)
+	}
+
+	lines := strings.Split(strings.TrimSpace(w.buf.String()), "\n")
+	slices.Sort(lines)
+	want := []string{`"https://example.com/list/item/0"`, `"https://example.com/list/item/2"`}
+	if !slices.Equal(lines, want) {
+		t.Errorf("written lines = %q, want %q", lines, want)
+	}
+}
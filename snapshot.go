@@ -0,0 +1,85 @@
+package scrapify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+)
+
+// snapshot is the serialized state of a crawl returned by Snapshot: a checkpoint, along with the Stats of the crawl
+// and the fingerprint of its strategies.
+type snapshot struct {
+	checkpoint
+	Fingerprint string `json:"fingerprint"` // Fingerprint of the strategies, see fingerprint.
+	Stats       Stats  `json:"stats"`       // Stats of the crawl when the snapshot was taken.
+}
+
+// Snapshot serializes the live state of the crawl, its visited set, frontier and Stats, along with a fingerprint of its
+// strategies, for Restore to continue it in another Scraper, possibly on another machine. It is the explicit counterpart
+// of WithCheckpoint and requires a VisitedStore implementing ListableVisitedStore.
+// In-flight URLs are stored in the frontier, so they are processed again after Restore. For a consistent snapshot of a running
+// crawl, quiesce it first: call PauseDiscovery and PauseFetching and wait for InFlight to reach zero, then snapshot and cancel it.
+func (s *Scraper[T]) Snapshot() ([]byte, error) {
+	if _, ok := s.scrapedUrls.(ListableVisitedStore); !ok {
+		return nil, errors.New("scrapify: snapshots require a VisitedStore implementing ListableVisitedStore")
+	}
+
+	snap := snapshot{checkpoint: s.capture(), Fingerprint: s.fingerprint(), Stats: s.Stats()}
+	b, err := json.Marshal(snap)
+	if err != nil {
+		return nil, fmt.Errorf("scrapify: encoding snapshot: %w", err)
+	}
+	return b, nil
+}
+
+// Restore loads a snapshot taken with Snapshot, so the next Run resumes the crawl from its frontier, skipping its visited URLs,
+// with Stats counting on from those of the snapshot. It must be called before Run, on a Scraper with the same strategies,
+// in the same order, as the one the snapshot was taken from; otherwise it returns an error. A restored snapshot takes
+// precedence over the file of WithCheckpoint.
+func (s *Scraper[T]) Restore(b []byte) error {
+	var snap snapshot
+	if err := json.Unmarshal(b, &snap); err != nil {
+		return fmt.Errorf("scrapify: decoding snapshot: %w", err)
+	}
+	if snap.Fingerprint != s.fingerprint() {
+		return errors.New("scrapify: snapshot was taken with different strategies")
+	}
+	if err := s.checkCheckpoint(&snap.checkpoint); err != nil {
+		return err
+	}
+
+	s.restored = &snap.checkpoint
+	s.counters.restore(snap.Stats)
+	return nil
+}
+
+// fingerprint identifies the strategies of the Scraper, by their IDs and start URLs in order.
+func (s *Scraper[T]) fingerprint() string {
+	h := sha256.New()
+	for _, strategy := range s.strategy {
+		fmt.Fprintf(h, "%q %q\n", strategy.ID, strategy.Url)
+	}
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// restore sets the counters to the stats of a snapshot.
+func (c *counters) restore(st Stats) {
+	c.pages.Store(st.Pages)
+	c.empty.Store(st.Empty)
+	c.urls.Store(st.URLs)
+	c.items.Store(st.Items)
+	c.filtered.Store(st.Filtered)
+	c.duplicates.Store(st.Duplicates)
+	c.truncated.Store(st.Truncated)
+	c.errors.Store(st.Errors)
+	c.softFailures.Store(st.SoftFailures)
+	c.retries.Store(st.Retries)
+	c.bytes.Store(st.Bytes)
+
+	c.statusMu.Lock()
+	c.statuses = maps.Clone(st.StatusCodes)
+	c.statusMu.Unlock()
+}
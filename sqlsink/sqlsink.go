@@ -0,0 +1,231 @@
+// Package sqlsink provides a scrapify callback writing items to a database/sql target in batches.
+package sqlsink
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrClosed is reported for items received after the Sink was closed.
+var ErrClosed = errors.New("sqlsink: sink closed")
+
+// InsertFunc writes a batch of items within a transaction. The Sink commits the transaction when it returns nil
+// and rolls it back otherwise, so a batch is either fully written or not at all.
+type InsertFunc[T any] func(ctx context.Context, tx *sql.Tx, items []T) error
+
+// Insert returns an InsertFunc preparing query once per batch and executing it for every item, with the arguments returned by args.
+func Insert[T any](query string, args func(T) []any) InsertFunc[T] {
+	return func(ctx context.Context, tx *sql.Tx, items []T) error {
+		stmt, err := tx.PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, item := range items {
+			if _, err := stmt.ExecContext(ctx, args(item)...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// Sink buffers items and writes them to a database in batches, each in its own transaction.
+// Pass its Callback method to NewScraper, and call Close once the crawl is over to write the last batch:
+//
+//	sink := sqlsink.NewSQLSink(db, sqlsink.Insert("INSERT INTO products (name, price) VALUES (?, ?)",
+//		func(p Product) []any { return []any{p.Name, p.Price} }), sqlsink.OnError(logError))
+//	scraper := scrapify.NewScraper(strategies, sink.Callback, 0, scrapify.OnError(logError))
+//	err := scraper.Run(ctx)
+//	err = errors.Join(err, sink.Close(ctx))
+//
+// It is safe for concurrent use.
+type Sink[T any] struct {
+	db        *sql.DB          // Database the items are written to.
+	insert    InsertFunc[T]    // Writes a batch within a transaction.
+	batchSize int              // Number of items buffered before a batch is written.
+	retries   int              // Number of retries of a batch failing with a transient error.
+	backoff   time.Duration    // Wait before the first retry, doubled on each retry.
+	transient func(error) bool // Reports whether a failed batch can be retried.
+	onError   func(error)      // Invoked with the errors of the batches written by Callback.
+	mu        sync.Mutex       // Guards batch and closed, and serializes writes.
+	batch     []T              // Items buffered since the last write.
+	closed    bool             // Whether Close was called.
+}
+
+// Option configures a Sink.
+type Option func(*config)
+
+// config holds the optional configuration of a Sink.
+type config struct {
+	batchSize int              // Number of items buffered before a batch is written.
+	retries   int              // Number of retries of a batch failing with a transient error.
+	backoff   time.Duration    // Wait before the first retry.
+	transient func(error) bool // Reports whether a failed batch can be retried.
+	onError   func(error)      // Invoked with the errors of the batches written by Callback.
+}
+
+// WithBatchSize writes the items in batches of n. The default is 100.
+func WithBatchSize(n int) Option {
+	return func(c *config) {
+		if n > 0 {
+			c.batchSize = n
+		}
+	}
+}
+
+// WithRetries retries a batch failing with a transient error up to n times, waiting backoff before the first retry
+// and doubling the wait on each retry. The default is 3 retries starting at 100ms.
+func WithRetries(n int, backoff time.Duration) Option {
+	return func(c *config) {
+		c.retries = n
+		c.backoff = backoff
+	}
+}
+
+// WithTransientErrors sets the function telling which errors are transient and worth retrying.
+// The default only retries driver.ErrBadConn; drivers reporting deadlocks or serialization failures need their own check.
+func WithTransientErrors(fn func(error) bool) Option {
+	return func(c *config) {
+		if fn != nil {
+			c.transient = fn
+		}
+	}
+}
+
+// OnError registers a hook invoked with the error of every batch written by Callback that could not be written,
+// whose items are dropped. It can be the hook given to scrapify.OnError, so database errors are reported with the crawl's.
+func OnError(fn func(error)) Option {
+	return func(c *config) {
+		c.onError = fn
+	}
+}
+
+// NewSQLSink creates a Sink writing items to db with insert.
+func NewSQLSink[T any](db *sql.DB, insert InsertFunc[T], opts ...Option) *Sink[T] {
+	c := config{
+		batchSize: 100,
+		retries:   3,
+		backoff:   100 * time.Millisecond,
+		transient: func(err error) bool { return errors.Is(err, driver.ErrBadConn) },
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return &Sink[T]{
+		db:        db,
+		insert:    insert,
+		batchSize: c.batchSize,
+		retries:   c.retries,
+		backoff:   c.backoff,
+		transient: c.transient,
+		onError:   c.onError,
+	}
+}
+
+// Callback buffers the item, writing the batch once it is full. It has the signature of the callback expected by NewScraper.
+// Errors are passed to the OnError hook, if any.
+func (s *Sink[T]) Callback(item T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		s.report(ErrClosed)
+		return
+	}
+
+	s.batch = append(s.batch, item)
+	if len(s.batch) >= s.batchSize {
+		s.report(s.flush(context.Background()))
+	}
+}
+
+// Flush writes the buffered items, if any, and returns the error of the write.
+func (s *Sink[T]) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.flush(ctx)
+}
+
+// Close writes the buffered items and closes the Sink, so items received afterwards are rejected with ErrClosed.
+// It does not close the database.
+func (s *Sink[T]) Close(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.flush(ctx)
+}
+
+// flush writes the buffered items. The items of a batch that cannot be written are dropped.
+// It must be called with mu held.
+func (s *Sink[T]) flush(ctx context.Context) error {
+	if len(s.batch) == 0 {
+		return nil
+	}
+
+	items := s.batch
+	s.batch = nil
+	if err := s.write(ctx, items); err != nil {
+		return fmt.Errorf("sqlsink: writing batch of %d items: %w", len(items), err)
+	}
+	return nil
+}
+
+// write writes a batch in a transaction, retrying transient errors.
+func (s *Sink[T]) write(ctx context.Context, items []T) error {
+	backoff := s.backoff
+	for attempt := 0; ; attempt++ {
+		err := s.tx(ctx, items)
+		if err == nil || attempt >= s.retries || !s.transient(err) {
+			return err
+		}
+
+		t := time.NewTimer(backoff)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return errors.Join(err, ctx.Err())
+		}
+		backoff *= 2
+	}
+}
+
+// tx writes a batch in a single transaction.
+func (s *Sink[T]) tx(ctx context.Context, items []T) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := s.insert(ctx, tx, items); err != nil {
+		return errors.Join(err, ignoreDone(tx.Rollback()))
+	}
+	return tx.Commit()
+}
+
+// ignoreDone drops the error of rolling back a transaction the driver already ended.
+func ignoreDone(err error) error {
+	if errors.Is(err, sql.ErrTxDone) {
+		return nil
+	}
+	return err
+}
+
+// report passes a non-nil error to the OnError hook, if any.
+func (s *Sink[T]) report(err error) {
+	if err != nil && s.onError != nil {
+		s.onError(err)
+	}
+}
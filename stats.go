@@ -0,0 +1,193 @@
+package scrapify
+
+import (
+	"maps"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats summarizes the progress of a crawl.
+type Stats struct {
+	Pages        int64         // Pages processed with GetUrls, including failed ones.
+	Empty        int64         // Pages for which GetUrls returned neither item URLs nor next pages.
+	URLs         int64         // Item URLs processed with GetData, including failed ones.
+	Items        int64         // Items delivered to the callback.
+	Filtered     int64         // Items dropped by the WithTransform function.
+	Duplicates   int64         // Items dropped as duplicates, by the canonical URL of their item URL or by WithItemDedup.
+	Truncated    int64         // Item URLs dropped by WithMaxURLsPerPage.
+	Errors       int64         // Errors reported by the scrapers, after retries.
+	SoftFailures int64         // Errors that were a SoftFailure, also counted in Errors.
+	Retries      int64         // Retries of failed GetUrls and GetData calls.
+	StatusCodes  map[int]int64 // Number of responses recorded with RecordResponse, by HTTP status code.
+	Bytes        int64         // Size of the response bodies recorded with RecordResponse.
+	QueueDepth   int           // Pages and item URLs waiting to be processed, see Scraper.QueueDepth.
+	InFlight     int           // Pages and item URLs being processed, see Scraper.InFlight.
+	Concurrency  int           // Current worker limit, with WithAdaptiveConcurrency.
+	Goroutines   int64         // Number of goroutines at the last sample, with WithPprof.
+	HeapAlloc    uint64        // Bytes of allocated heap objects at the last sample, with WithPprof.
+	StartedAt    time.Time     // When the crawl started.
+	Duration     time.Duration // How long the crawl has been running, or ran once finished.
+	FirstItem    time.Duration // Time from the start of the crawl to the delivery of its first item (0 until then).
+}
+
+// counters holds the live counters behind Stats.
+type counters struct {
+	pages        atomic.Int64  // See Stats.Pages.
+	empty        atomic.Int64  // See Stats.Empty.
+	urls         atomic.Int64  // See Stats.URLs.
+	items        atomic.Int64  // See Stats.Items.
+	filtered     atomic.Int64  // See Stats.Filtered.
+	duplicates   atomic.Int64  // See Stats.Duplicates.
+	truncated    atomic.Int64  // See Stats.Truncated.
+	errors       atomic.Int64  // See Stats.Errors.
+	softFailures atomic.Int64  // See Stats.SoftFailures.
+	retries      atomic.Int64  // See Stats.Retries.
+	statusMu     sync.Mutex    // Guards statuses.
+	statuses     map[int]int64 // See Stats.StatusCodes.
+	bytes        atomic.Int64  // See Stats.Bytes.
+	goroutines   atomic.Int64  // See Stats.Goroutines.
+	heapAlloc    atomic.Uint64 // See Stats.HeapAlloc.
+	startedAt    atomic.Int64  // Start time of the crawl, in Unix nanoseconds.
+	endedAt      atomic.Int64  // End time of the crawl, in Unix nanoseconds (0 while running).
+	firstItemAt  atomic.Int64  // Delivery time of the first item, in Unix nanoseconds (0 before it).
+}
+
+// OnComplete registers a hook invoked exactly once when Run returns, after all work has drained and the last callback has returned.
+// It also fires when the crawl is stopped early or cancelled, with stats reflecting the partial progress.
+// It is the place to flush buffers, close connections or log a summary.
+func OnComplete(fn func(stats Stats)) Option {
+	return func(o *options) {
+		o.onComplete = fn
+	}
+}
+
+// Stats returns a snapshot of the progress of the crawl. It is safe to call while the crawl is running.
+func (s *Scraper[T]) Stats() Stats {
+	st := s.counters.stats(s.clock.Now())
+
+	s.mu.Lock()
+	st.QueueDepth = s.scheduler.Len() + len(s.parked)
+	st.InFlight = s.inFlight()
+	s.mu.Unlock()
+
+	if s.adaptive != nil {
+		st.Concurrency = s.adaptive.current()
+	}
+	return st
+}
+
+// StatsByStrategy returns a snapshot of the progress of each strategy, by strategy ID, to tell which sites of a crawl
+// succeeded and which failed. It is safe to call while the crawl is running.
+// The counters are those of Stats restricted to the URLs of the strategy, and StartedAt and Duration
+// those of the last crawl of the strategy, by Run or RunStrategy.
+// QueueDepth, InFlight, Concurrency, Goroutines and HeapAlloc are only reported by Stats and are zero.
+func (s *Scraper[T]) StatsByStrategy() map[string]Stats {
+	now := s.clock.Now()
+	stats := make(map[string]Stats, len(s.strategy))
+	for i, strategy := range s.strategy {
+		stats[strategy.ID] = s.strategyStats[i].stats(now)
+	}
+	return stats
+}
+
+// start records the start of the crawl.
+func (s *Scraper[T]) start() {
+	now := s.clock.Now().UnixNano()
+	s.counters.startedAt.Store(now)
+	for i, c := range s.strategyStats {
+		if s.seeds(i) {
+			c.startedAt.Store(now)
+		}
+	}
+}
+
+// complete records the end of the crawl and invokes the OnComplete hook.
+func (s *Scraper[T]) complete() {
+	now := s.clock.Now().UnixNano()
+	s.counters.endedAt.Store(now)
+	for i, c := range s.strategyStats {
+		if s.seeds(i) {
+			c.endedAt.Store(now)
+		}
+	}
+
+	if s.onComplete != nil {
+		s.onComplete(s.Stats())
+	}
+}
+
+// newStrategyCounters creates the counters of n strategies.
+func newStrategyCounters(n int) []*counters {
+	c := make([]*counters, n)
+	for i := range c {
+		c[i] = &counters{}
+	}
+	return c
+}
+
+// stats returns a snapshot of the counters at the given time.
+func (c *counters) stats(now time.Time) Stats {
+	st := Stats{
+		Pages:        c.pages.Load(),
+		Empty:        c.empty.Load(),
+		URLs:         c.urls.Load(),
+		Items:        c.items.Load(),
+		Filtered:     c.filtered.Load(),
+		Duplicates:   c.duplicates.Load(),
+		Truncated:    c.truncated.Load(),
+		Errors:       c.errors.Load(),
+		SoftFailures: c.softFailures.Load(),
+		Retries:      c.retries.Load(),
+		Bytes:        c.bytes.Load(),
+		Goroutines:   c.goroutines.Load(),
+		HeapAlloc:    c.heapAlloc.Load(),
+	}
+
+	c.statusMu.Lock()
+	st.StatusCodes = maps.Clone(c.statuses)
+	c.statusMu.Unlock()
+
+	if started := c.startedAt.Load(); started != 0 {
+		st.StartedAt = time.Unix(0, started)
+		end := now
+		if ended := c.endedAt.Load(); ended != 0 {
+			end = time.Unix(0, ended)
+		}
+		st.Duration = end.Sub(st.StartedAt)
+		if first := c.firstItemAt.Load(); first != 0 {
+			st.FirstItem = time.Unix(0, first).Sub(st.StartedAt)
+		}
+	}
+	return st
+}
+
+// reset zeroes the counters, for a strategy crawled again with RunStrategy.
+func (c *counters) reset() {
+	for _, n := range []*atomic.Int64{&c.pages, &c.empty, &c.urls, &c.items, &c.filtered, &c.duplicates, &c.truncated, &c.errors,
+		&c.softFailures, &c.retries, &c.bytes, &c.goroutines, &c.startedAt, &c.endedAt, &c.firstItemAt} {
+		n.Store(0)
+	}
+	c.heapAlloc.Store(0)
+
+	c.statusMu.Lock()
+	c.statuses = nil
+	c.statusMu.Unlock()
+}
+
+// delivered counts an item delivered at the given time.
+func (c *counters) delivered(at time.Time) {
+	c.items.Add(1)
+	c.firstItemAt.CompareAndSwap(0, at.UnixNano())
+}
+
+// status counts a response with the given status code.
+func (c *counters) status(code int) {
+	c.statusMu.Lock()
+	defer c.statusMu.Unlock()
+
+	if c.statuses == nil {
+		c.statuses = make(map[int]int64)
+	}
+	c.statuses[code]++
+}
@@ -0,0 +1,79 @@
+package scrapify_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+func TestItemsAreDeliveredDuringDeepPagination(t *testing.T) {
+	const depth = 40
+
+	var discovered atomic.Int64
+	var discoveredAtFirstItem int64 = -1
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+		Scraper: funcScraper[string]{
+			urls: func(ctx context.Context, url string) ([]string, []string, error) {
+				time.Sleep(2 * time.Millisecond)
+				discovered.Add(1)
+
+				page, _ := strconv.Atoi(url[strings.LastIndex(url, "=")+1:])
+				items := []string{fmt.Sprintf("https://example.com/item/%d-a", page), fmt.Sprintf("https://example.com/item/%d-b", page)}
+				if page == depth {
+					return items, nil, nil
+				}
+				return items, []string{fmt.Sprintf("https://example.com/list?page=%d", page+1)}, nil
+			},
+			data: echo,
+		},
+		Url: "https://example.com/list?page=1",
+	}}, func(string) {
+		if discoveredAtFirstItem < 0 {
+			discoveredAtFirstItem = discovered.Load()
+		}
+	}, 0)
+
+	if err := scraper.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	stats := scraper.Stats()
+	if stats.Items != 2*depth {
+		t.Fatalf("Stats().Items = %d, want %d", stats.Items, 2*depth)
+	}
+	// Items must flow while later pages are still being discovered, not once pagination is exhausted.
+	if discoveredAtFirstItem >= depth/4 {
+		t.Errorf("first item delivered after %d of %d pages were discovered, want it delivered early", discoveredAtFirstItem, depth)
+	}
+	if stats.FirstItem <= 0 || stats.FirstItem > stats.Duration/4 {
+		t.Errorf("Stats().FirstItem = %v for a crawl of %v, want the first item early in the crawl", stats.FirstItem, stats.Duration)
+	}
+}
+
+func TestInFlightExcludesSeeding(t *testing.T) {
+	var scraper *scrapify.Scraper[string]
+	var inFlight, statsInFlight int
+	scraper = scrapify.NewScraper([]scrapify.ScraperStrategy[string]{
+		{
+			Scraper: funcScraper[string]{urls: func(ctx context.Context, url string) ([]string, []string, error) {
+				// The second start page waits for the strategy slot held by this one.
+				inFlight, statsInFlight = scraper.InFlight(), scraper.Stats().InFlight
+				return nil, nil, nil
+			}},
+			Url: "https://example.com/a",
+		},
+		{Scraper: funcScraper[string]{}, Url: "https://example.com/b"},
+	}, nil, 0, scrapify.WithMaxConcurrentStrategies(1))
+
+	if err := scraper.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if inFlight != 1 || statsInFlight != 1 {
+		t.Errorf("InFlight() = %d and Stats().InFlight = %d while seeding, want 1", inFlight, statsInFlight)
+	}
+}
@@ -0,0 +1,77 @@
+package scrapify
+
+import (
+	"context"
+	"errors"
+)
+
+// StopReason tells why a crawl stopped.
+type StopReason string
+
+const (
+	// StopCompleted means all the work of the crawl was done.
+	StopCompleted StopReason = "completed"
+
+	// StopCancelled means the context given to Run was cancelled.
+	StopCancelled StopReason = "cancelled"
+
+	// StopDeadline means the deadline of the context given to Run was exceeded.
+	StopDeadline StopReason = "deadline"
+
+	// StopFirstError means the crawl was stopped by its first error, with WithFirstErrorStops.
+	StopFirstError StopReason = "first-error"
+
+	// StopMaxErrors means the crawl was aborted because it recorded more errors than allowed by WithMaxErrors.
+	StopMaxErrors StopReason = "max-errors"
+
+	// StopErrorRate means the crawl was aborted because its error rate exceeded the one allowed by WithMaxErrorRate.
+	StopErrorRate StopReason = "error-rate"
+
+	// StopMaxBytes means the crawl was stopped because it downloaded the bytes allowed by WithMaxBytes.
+	StopMaxBytes StopReason = "max-bytes"
+
+	// StopMaxItems means the crawl was stopped because it delivered the items allowed by WithMaxItems.
+	StopMaxItems StopReason = "max-items"
+)
+
+// stopCause is the cause the crawl context is cancelled with when the Scraper stops the crawl itself.
+type stopCause struct {
+	reason StopReason // Why the crawl was stopped.
+}
+
+// Error describes the stop.
+func (c *stopCause) Error() string {
+	return "scrapify: crawl stopped: " + string(c.reason)
+}
+
+// StopReason returns why the last crawl stopped, once Run has returned or the done channel of Start has been closed.
+// It is empty while the crawl runs, and when Run failed before crawling, such as on invalid strategies.
+func (s *Scraper[T]) StopReason() StopReason {
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+
+	return s.stopReason
+}
+
+// stop cancels the crawl for the given reason.
+func (s *Scraper[T]) stop(reason StopReason) {
+	s.cancel(&stopCause{reason: reason})
+}
+
+// stopReason returns why the crawl running under ctx stopped, from the cause of its cancellation.
+func stopReason(ctx context.Context) StopReason {
+	cause := context.Cause(ctx)
+	if cause == nil {
+		return StopCompleted
+	}
+
+	var stop *stopCause
+	switch {
+	case errors.As(cause, &stop):
+		return stop.reason
+	case errors.Is(cause, context.DeadlineExceeded):
+		return StopDeadline
+	default:
+		return StopCancelled
+	}
+}
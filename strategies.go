@@ -0,0 +1,183 @@
+package scrapify
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// WithMaxConcurrentStrategies limits the number of strategies active at the same time to n.
+// A strategy is active from the scheduling of its start page until all the pages and item URLs it discovered are processed,
+// so seeds are processed in bounded waves instead of all at once. This is distinct from WithConcurrency,
+// which bounds the number of URLs processed at once. Work resumed from a checkpoint, or enqueued from OnItem
+// once its strategy is no longer active, runs without waiting for a slot. The default of zero is unlimited.
+func WithMaxConcurrentStrategies(n int) Option {
+	return func(o *options) {
+		o.maxStrategies = n
+	}
+}
+
+// acquireStrategy waits for a free strategy slot for the given strategy, and reports false if the context is done first.
+func (s *Scraper[T]) acquireStrategy(ctx context.Context, strategy int) bool {
+	if s.strategySlots == nil {
+		return true
+	}
+
+	select {
+	case s.strategySlots <- struct{}{}:
+	case <-ctx.Done():
+		return false
+	}
+
+	s.mu.Lock()
+	s.holding[strategy] = true
+	s.mu.Unlock()
+	return true
+}
+
+// releaseStrategy frees the slot held by the strategy once it has no pending or in-flight work left.
+func (s *Scraper[T]) releaseStrategy(strategy int) {
+	s.mu.Lock()
+	release := s.holding[strategy] && s.active[strategy] == 0
+	if release {
+		s.holding[strategy] = false
+	}
+	s.mu.Unlock()
+
+	if release {
+		<-s.strategySlots
+	}
+}
+
+// done records that a work item has been executed, releasing the slot of its strategy if this was its last work.
+func (s *Scraper[T]) done(w Work) {
+	s.mu.Lock()
+	s.active[w.Strategy]--
+	s.mu.Unlock()
+
+	s.releaseStrategy(w.Strategy)
+}
+
+// FailedStrategies returns the IDs of the strategies that recorded errors during the last crawl, in the order of the strategies.
+// A strategy re-run with RunStrategy is reported according to its re-run.
+func (s *Scraper[T]) FailedStrategies() []string {
+	var ids []string
+	for i, strategy := range s.strategy {
+		if s.strategyStats[i].errors.Load() > 0 {
+			ids = append(ids, strategy.ID)
+		}
+	}
+	return ids
+}
+
+// RunStrategy crawls the strategy with the given ID again, on its own, to recover from its failure without redoing the others,
+// typically for the IDs returned by FailedStrategies once the host of the strategy is back up.
+// The strategy is crawled from its start URL with the same options as Run, except that it starts with an empty visited set
+// and a FIFOScheduler, or a new ScoredScheduler with WithURLScorer, so the URLs it already scraped are scraped again, and that it is not checkpointed.
+// The callback and the hooks are invoked as for Run, but Stats, StopReason and Err keep describing the last call to Run,
+// while the StatsByStrategy of the strategy start over to describe its re-run.
+// It returns the error Run would have returned for the strategy alone.
+func (s *Scraper[T]) RunStrategy(ctx context.Context, id string) error {
+	i := slices.IndexFunc(s.strategy, func(strategy ScraperStrategy[T]) bool { return strategy.ID == id })
+	if i < 0 {
+		return fmt.Errorf("scrapify: unknown strategy %q", id)
+	}
+
+	o := s.options
+	o.visited = NewMemoryVisitedStore()
+	o.scheduler = NewFIFOScheduler()
+	if o.urlScorer != nil {
+		o.scheduler = NewScoredScheduler(o.urlScorer)
+	}
+	o.checkpointPath = ""
+
+	// The re-run delivers to the callback given to NewScraper, never to the hooks of a running RunStream or RunAndCollect.
+	rerun := newScraper(s.strategy, s.callback, s.requestDelay, o)
+	rerun.single = i
+	s.strategyStats[i].reset()
+	rerun.strategyStats = s.strategyStats
+	return rerun.Run(ctx)
+}
+
+// seeds reports whether the start page of the strategy is scheduled when the crawl starts.
+func (s *Scraper[T]) seeds(strategy int) bool {
+	return s.single < 0 || s.single == strategy
+}
+
+// withinDepth reports whether the work is within the MaxDepth of its strategy.
+func (s *Scraper[T]) withinDepth(w Work) bool {
+	limit := s.strategy[w.Strategy].MaxDepth
+	return limit <= 0 || w.Depth <= limit
+}
+
+// takePage claims one of the MaxPages of the strategy for a page about to be visited,
+// and reports false once the strategy has claimed them all.
+func (s *Scraper[T]) takePage(strategy int) bool {
+	limit := s.strategy[strategy].MaxPages
+	if limit <= 0 {
+		return true
+	}
+	if s.strategyPages[strategy].Add(1) > int64(limit) {
+		s.strategyPages[strategy].Add(-1)
+		return false
+	}
+	return true
+}
+
+// returnPage gives back the page claimed by takePage for a page that was not visited after all.
+func (s *Scraper[T]) returnPage(strategy int) {
+	if s.strategy[strategy].MaxPages > 0 {
+		s.strategyPages[strategy].Add(-1)
+	}
+}
+
+// StrategyScheduler keeps a separate frontier for each strategy and pops them in turn, one work at a time,
+// so every strategy of a multi-strategy crawl progresses at the same pace whatever the size of its backlog,
+// while the strategies still share the visited set. The frontier of each strategy is a Scheduler of its own,
+// deciding the order of the work of that strategy.
+type StrategyScheduler struct {
+	newFrontier func() Scheduler  // Creates the frontier of a strategy.
+	frontiers   map[int]Scheduler // Frontier of each strategy that had work pushed, by strategy index.
+	order       []int             // Strategies with a frontier, in the order they were first pushed work.
+	next        int               // Index in order of the strategy popped next.
+}
+
+// NewStrategyScheduler creates a StrategyScheduler whose frontiers are created by newFrontier, a FIFOScheduler if it is nil.
+func NewStrategyScheduler(newFrontier func() Scheduler) *StrategyScheduler {
+	if newFrontier == nil {
+		newFrontier = func() Scheduler { return NewFIFOScheduler() }
+	}
+	return &StrategyScheduler{newFrontier: newFrontier, frontiers: make(map[int]Scheduler)}
+}
+
+// Push offers the work to the frontier of its strategy.
+func (p *StrategyScheduler) Push(w Work) bool {
+	frontier, ok := p.frontiers[w.Strategy]
+	if !ok {
+		frontier = p.newFrontier()
+		p.frontiers[w.Strategy] = frontier
+		p.order = append(p.order, w.Strategy)
+	}
+	return frontier.Push(w)
+}
+
+// Pop removes and returns the next work of the next strategy with pending work, in turn.
+func (p *StrategyScheduler) Pop() (Work, bool) {
+	for range p.order {
+		strategy := p.order[p.next]
+		p.next = (p.next + 1) % len(p.order)
+		if w, ok := p.frontiers[strategy].Pop(); ok {
+			return w, true
+		}
+	}
+	return Work{}, false
+}
+
+// Len returns the number of pending work items across the frontiers.
+func (p *StrategyScheduler) Len() int {
+	n := 0
+	for _, frontier := range p.frontiers {
+		n += frontier.Len()
+	}
+	return n
+}
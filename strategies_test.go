@@ -0,0 +1,125 @@
+package scrapify_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+// chain is a GetUrls function for a site whose pages /p/0 to /p/5 each hold one item URL and link to the next page,
+// while /b only links to /p/2.
+func chain(ctx context.Context, url string) ([]string, []string, error) {
+	if strings.HasSuffix(url, "/b") {
+		return nil, []string{"https://example.com/p/2"}, nil
+	}
+
+	var page int
+	fmt.Sscanf(url[strings.LastIndex(url, "/")+1:], "%d", &page)
+	var next []string
+	if page < 5 {
+		next = []string{fmt.Sprintf("https://example.com/p/%d", page+1)}
+	}
+	return []string{url + "/item"}, next, nil
+}
+
+func TestStrategyLimitsLeaveURLsToOtherStrategies(t *testing.T) {
+	var got collector[string]
+	site := funcScraper[string]{urls: chain, data: echo}
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{
+		{ID: "a", Scraper: site, Url: "https://example.com/p/0", MaxPages: 2},
+		{ID: "b", Scraper: site, Url: "https://example.com/b"},
+	}, got.add, 0, scrapify.WithMaxConcurrentStrategies(1))
+
+	if err := scraper.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	// The pages beyond the limit of a are not visited, so b crawls them.
+	items := got.result()
+	slices.Sort(items)
+	var want []string
+	for i := range 6 {
+		want = append(want, fmt.Sprintf("https://example.com/p/%d/item", i))
+	}
+	if !slices.Equal(items, want) {
+		t.Errorf("items %v, want %v", items, want)
+	}
+	stats := scraper.StatsByStrategy()
+	if stats["a"].Pages != 2 || stats["b"].Pages != 5 {
+		t.Errorf("a crawled %d pages and b %d, want 2 and 5", stats["a"].Pages, stats["b"].Pages)
+	}
+}
+
+func TestStrategyMaxDepth(t *testing.T) {
+	var got collector[string]
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{
+		{Scraper: funcScraper[string]{urls: chain, data: echo}, Url: "https://example.com/p/0", MaxDepth: 1},
+	}, got.add, 0)
+
+	if err := scraper.Run(context.Background()); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if items := got.result(); !slices.Equal(items, []string{"https://example.com/p/0/item"}) {
+		t.Errorf("items %v, want only the one at depth 1", items)
+	}
+	if pages := scraper.Stats().Pages; pages != 2 {
+		t.Errorf("crawled %d pages, want the 2 up to depth 1", pages)
+	}
+}
+
+func TestStrategySchedulerPopsStrategiesInTurn(t *testing.T) {
+	scheduler := scrapify.NewStrategyScheduler(nil)
+	for _, w := range []scrapify.Work{
+		{URL: "a1", Strategy: 0}, {URL: "a2", Strategy: 0}, {URL: "a3", Strategy: 0}, {URL: "b1", Strategy: 1}, {URL: "c1", Strategy: 2},
+	} {
+		scheduler.Push(w)
+	}
+	if n := scheduler.Len(); n != 5 {
+		t.Errorf("Len() = %d, want 5", n)
+	}
+
+	var got []string
+	for {
+		w, ok := scheduler.Pop()
+		if !ok {
+			break
+		}
+		got = append(got, w.URL)
+	}
+	if want := []string{"a1", "b1", "c1", "a2", "a3"}; !slices.Equal(got, want) {
+		t.Errorf("popped %v, want %v", got, want)
+	}
+}
+
+func TestRunStrategyDuringRunAndCollect(t *testing.T) {
+	var got collector[string]
+	var scraper *scrapify.Scraper[string]
+	var rerun atomic.Bool
+	scraper = scrapify.NewScraper([]scrapify.ScraperStrategy[string]{
+		{ID: "a", Scraper: funcScraper[string]{urls: listing(2), data: echo}, Url: "https://a.example/list"},
+		{ID: "b", Scraper: funcScraper[string]{urls: listing(2), data: echo}, Url: "https://b.example/list"},
+	}, func(item string) {
+		got.add(item)
+		if rerun.CompareAndSwap(false, true) {
+			if err := scraper.RunStrategy(context.Background(), "b"); err != nil {
+				t.Errorf("RunStrategy: %v", err)
+			}
+		}
+	}, 0)
+
+	items, err := scraper.RunAndCollect(context.Background())
+	if err != nil {
+		t.Fatalf("RunAndCollect: %v", err)
+	}
+	if len(items) != 4 {
+		t.Errorf("RunAndCollect returned %d items, want the 4 of the crawl without those of the re-run", len(items))
+	}
+	if n := len(got.result()); n != 6 {
+		t.Errorf("callback received %d items, want the 4 of the crawl and the 2 of the re-run", n)
+	}
+}
@@ -0,0 +1,85 @@
+package scrapify
+
+import (
+	"context"
+	"sync"
+)
+
+// EventKind identifies the kind of an Event.
+type EventKind int
+
+const (
+	// EventItem carries an item scraped by GetData.
+	EventItem EventKind = iota
+
+	// EventError carries an error returned by GetUrls or GetData.
+	EventError
+
+	// EventComplete is the last event of a stream, sent once the crawl has ended.
+	// Its Err field holds the error Run would have returned, and its Reason why the crawl stopped.
+	EventComplete
+)
+
+// Event is a single notification of a crawl streamed by RunStream.
+// Only the fields relevant to its Kind are set.
+type Event[T any] struct {
+	Kind   EventKind  // What the event is about.
+	Item   T          // The scraped item, for EventItem.
+	Err    error      // The error, for EventError and EventComplete.
+	Reason StopReason // Why the crawl stopped, for EventComplete (empty when Run failed before crawling).
+}
+
+// Completed reports whether the event is the EventComplete of a crawl that did all its work, as opposed to one that was
+// cancelled, stopped early or failed to start. Errors of individual URLs do not prevent a crawl from completing.
+func (e Event[T]) Completed() bool {
+	return e.Kind == EventComplete && e.Reason == StopCompleted
+}
+
+// RunStream starts the crawl in the background and streams its items, errors and completion on the returned channel.
+// Items and errors are delivered as they happen, then a final EventComplete is sent and the channel is closed.
+// The EventComplete tells consumers whether the crawl completed or why it stopped, which the closing of the channel alone does not.
+// The callback given to NewScraper, if any, is still invoked for every item.
+// The channel must be drained until it is closed, otherwise the crawl blocks.
+// Errors of calls abandoned with WithDrainTimeout that fail after the channel is closed are not streamed.
+// The stream stops receiving items and errors before the EventComplete, so RunStrategy can re-run a strategy afterwards.
+func (s *Scraper[T]) RunStream(ctx context.Context) <-chan Event[T] {
+	events := make(chan Event[T])
+
+	// Abandoned calls may report errors after Run has returned, once events is closed.
+	var mu sync.RWMutex
+	closed := false
+
+	itemHook, errorHook := s.itemHook, s.errorHook
+	s.itemHook = func(item T) {
+		events <- Event[T]{Kind: EventItem, Item: item}
+	}
+	s.errorHook = func(err error) {
+		mu.RLock()
+		defer mu.RUnlock()
+
+		if closed {
+			return
+		}
+		select {
+		case events <- Event[T]{Kind: EventError, Err: err}:
+		case <-s.abandoned:
+		}
+	}
+
+	go func() {
+		err := s.Run(ctx)
+		s.itemHook = itemHook
+		s.errMu.Lock()
+		s.errorHook = errorHook
+		s.errMu.Unlock()
+		events <- Event[T]{Kind: EventComplete, Err: err, Reason: s.StopReason()}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		closed = true
+		close(events)
+	}()
+
+	return events
+}
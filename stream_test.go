@@ -0,0 +1,70 @@
+package scrapify_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+func TestRunStreamIgnoresErrorsOfAbandonedWork(t *testing.T) {
+	release := make(chan struct{})
+	reported := make(chan error, 1)
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+		Scraper: funcScraper[string]{urls: listing(1), data: func(ctx context.Context, ch chan<- string, url string) error {
+			<-release
+			return errors.New("late failure")
+		}},
+		Url: "https://example.com/list",
+	}}, nil, 0, scrapify.WithDrainTimeout(10*time.Millisecond), scrapify.OnError(func(err error) { reported <- err }))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	var last scrapify.Event[string]
+	for event := range scraper.RunStream(ctx) {
+		last = event
+	}
+	if last.Kind != scrapify.EventComplete || last.Reason != scrapify.StopDeadline {
+		t.Fatalf("last event = %+v, want the completion of a crawl stopped by its deadline", last)
+	}
+
+	// The abandoned call fails once the stream is closed, which must not send on it.
+	close(release)
+	select {
+	case <-reported:
+	case <-time.After(time.Second):
+		t.Fatal("the error of the abandoned call was not reported")
+	}
+}
+
+func TestRunStrategyAfterRunStream(t *testing.T) {
+	var got collector[string]
+	var failing atomic.Bool
+	failing.Store(true)
+	scraper := scrapify.NewScraper([]scrapify.ScraperStrategy[string]{{
+		Scraper: funcScraper[string]{urls: listing(2), data: func(ctx context.Context, ch chan<- string, url string) error {
+			if failing.Load() {
+				return errors.New("host down")
+			}
+			ch <- url
+			return nil
+		}},
+		Url: "https://example.com/list",
+		ID:  "shop",
+	}}, got.add, 0)
+
+	for range scraper.RunStream(context.Background()) {
+	}
+
+	// The re-run delivers to the callback given to NewScraper, not to the closed stream.
+	failing.Store(false)
+	if err := scraper.RunStrategy(context.Background(), "shop"); err != nil {
+		t.Fatalf("RunStrategy: %v", err)
+	}
+	if n := len(got.result()); n != 2 {
+		t.Errorf("callback received %d items, want the 2 of the re-run", n)
+	}
+}
@@ -0,0 +1,89 @@
+package scrapify
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// StrategiesFromTemplate expands a URL template over sets of parameter values into strategies sharing one scraper,
+// such as one strategy per category for "https://example.com/category/{id}/page/1".
+// Placeholders are names in braces, filled in order of first appearance with the values of each row of params,
+// formatted with fmt.Sprint and path-escaped; a name used several times takes the same value everywhere.
+// It returns an error if the template has a malformed placeholder or if a row does not have one value per placeholder.
+func StrategiesFromTemplate[T any](scraper IScraper[T], template string, params [][]any) ([]ScraperStrategy[T], error) {
+	parts, names, err := parseTemplate(template)
+	if err != nil {
+		return nil, err
+	}
+
+	strategies := make([]ScraperStrategy[T], 0, len(params))
+	for i, row := range params {
+		if len(row) != len(names) {
+			return nil, fmt.Errorf("scrapify: template %q has %d placeholders but params row %d has %d values", template, len(names), i, len(row))
+		}
+
+		values := make(map[string]string, len(names))
+		for j, name := range names {
+			values[name] = url.PathEscape(fmt.Sprint(row[j]))
+		}
+
+		var b strings.Builder
+		for _, part := range parts {
+			if part.placeholder {
+				b.WriteString(values[part.text])
+			} else {
+				b.WriteString(part.text)
+			}
+		}
+		strategies = append(strategies, ScraperStrategy[T]{Scraper: scraper, Url: b.String()})
+	}
+	return strategies, nil
+}
+
+// templatePart is a literal piece of a URL template or the name of a placeholder.
+type templatePart struct {
+	text        string // Literal text, or placeholder name.
+	placeholder bool   // Whether text is a placeholder name.
+}
+
+// parseTemplate splits a URL template into parts and returns the distinct placeholder names in order of first appearance.
+func parseTemplate(template string) ([]templatePart, []string, error) {
+	var (
+		parts []templatePart
+		names []string
+		seen  = make(map[string]bool)
+	)
+
+	rest := template
+	for rest != "" {
+		open := strings.IndexAny(rest, "{}")
+		if open < 0 {
+			parts = append(parts, templatePart{text: rest})
+			break
+		}
+		if rest[open] == '}' {
+			return nil, nil, fmt.Errorf("scrapify: template %q has an unexpected '}'", template)
+		}
+
+		end := strings.IndexAny(rest[open+1:], "{}")
+		if end < 0 || rest[open+1+end] != '}' {
+			return nil, nil, fmt.Errorf("scrapify: template %q has an unclosed '{'", template)
+		}
+		name := rest[open+1 : open+1+end]
+		if name == "" {
+			return nil, nil, fmt.Errorf("scrapify: template %q has an empty placeholder", template)
+		}
+
+		if open > 0 {
+			parts = append(parts, templatePart{text: rest[:open]})
+		}
+		parts = append(parts, templatePart{text: name, placeholder: true})
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+		rest = rest[open+1+end+1:]
+	}
+	return parts, names, nil
+}
@@ -0,0 +1,25 @@
+package scrapify
+
+// WithTransform registers a function applied to every scraped item before the callback, to enrich or filter items
+// in one place instead of in every scraper. It returns the item to deliver, or false to drop it.
+// Dropped items reach neither the callback nor the OnItem handler, and are counted in Stats.Filtered.
+// T must be the type of data of the Scraper, otherwise Run and Probe fail without crawling.
+func WithTransform[T any](fn func(item T) (T, bool)) Option {
+	return func(o *options) {
+		o.transform = fn
+	}
+}
+
+// transformer returns the function registered with WithTransform, checking it matches the type of data of the Scraper.
+// A mismatch is added to errs and disables the option.
+func transformer[T any](o options, errs *[]error) func(T) (T, bool) {
+	if o.transform == nil {
+		return nil
+	}
+
+	fn, ok := o.transform.(func(T) (T, bool))
+	if !ok {
+		*errs = append(*errs, mismatch[T]("WithTransform function", o.transform))
+	}
+	return fn
+}
@@ -0,0 +1,98 @@
+package scrapify
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// AsAny adapts a scraper of a concrete type V to a Scraper[any], so scrapers of different types can run in the same crawl.
+// Items sent by the wrapped scraper are forwarded unchanged, keeping their dynamic type for a TypedCallback to route them.
+func AsAny[V any](scraper IScraper[V]) IScraper[any] {
+	return anyScraper[V]{scraper: scraper}
+}
+
+// anyScraper is the IScraper[any] returned by AsAny.
+type anyScraper[V any] struct {
+	scraper IScraper[V] // The typed scraper being adapted.
+}
+
+// GetUrls delegates to the wrapped scraper.
+func (a anyScraper[V]) GetUrls(ctx context.Context, url string) ([]string, []string, error) {
+	return a.scraper.GetUrls(ctx, url)
+}
+
+// GetData runs the wrapped scraper and forwards every item it sends to the untyped channel.
+func (a anyScraper[V]) GetData(ctx context.Context, ch chan<- any, data *any, url string) error {
+	typed := make(chan V)
+	errc := make(chan error, 1)
+
+	var v V
+	go func() {
+		defer close(typed)
+		errc <- a.scraper.GetData(ctx, typed, &v, url)
+	}()
+
+	for item := range typed {
+		ch <- item
+	}
+
+	*data = v
+	return <-errc
+}
+
+// TypedCallback routes the items of a heterogeneous crawl to a callback registered for their dynamic type.
+// Register callbacks with Handle and pass the Callback method to NewScraper:
+//
+//	router := scrapify.NewTypedCallback()
+//	scrapify.Handle(router, func(p Product) { ... })
+//	scrapify.Handle(router, func(r Review) { ... })
+//	scraper := scrapify.NewScraper(strategies, router.Callback, 0)
+//
+// It is safe for concurrent use.
+type TypedCallback struct {
+	mu       sync.RWMutex               // Guards the handlers and the fallback.
+	handlers map[reflect.Type]func(any) // Callbacks keyed by the dynamic type they handle.
+	fallback func(any)                  // Invoked for items of an unregistered type (may be nil).
+}
+
+// NewTypedCallback creates a TypedCallback without any registered callback.
+func NewTypedCallback() *TypedCallback {
+	return &TypedCallback{handlers: make(map[reflect.Type]func(any))}
+}
+
+// Handle registers fn as the callback for items whose dynamic type is V, replacing any previous callback for V.
+// Items are matched on their exact dynamic type, so V should be a concrete type rather than an interface.
+func Handle[V any](r *TypedCallback, fn func(V)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.handlers[reflect.TypeFor[V]()] = func(item any) {
+		fn(item.(V))
+	}
+}
+
+// Fallback sets the callback invoked for items whose type has no registered callback.
+// Without a fallback, such items are dropped.
+func (r *TypedCallback) Fallback(fn func(any)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.fallback = fn
+}
+
+// Callback routes the item to the callback registered for its dynamic type.
+// It has the signature of the callback expected by NewScraper for a Scraper[any].
+func (r *TypedCallback) Callback(item any) {
+	r.mu.RLock()
+	fn, ok := r.handlers[reflect.TypeOf(item)]
+	fallback := r.fallback
+	r.mu.RUnlock()
+
+	switch {
+	case ok:
+		fn(item)
+	case fallback != nil:
+		fallback(item)
+	}
+}
@@ -0,0 +1,96 @@
+package scrapify
+
+import (
+	"context"
+	"time"
+)
+
+// URL describes a discovered URL together with settings that only apply to it.
+type URL struct {
+	URL     string        // The URL to process.
+	Timeout time.Duration // Per-request timeout for this URL, overriding WithRequestTimeout when positive.
+}
+
+// URLDiscoverer can be implemented by an IScraper to discover URL descriptors instead of plain strings.
+// When implemented, DiscoverURLs is called in place of GetUrls, so settings such as a longer timeout
+// can be attached to the few URLs that need them.
+type URLDiscoverer interface {
+	// DiscoverURLs retrieves the item URLs of the current page and the next pages for pagination.
+	DiscoverURLs(ctx context.Context, url string) ([]URL, []URL, error)
+}
+
+// WithRequestTimeout bounds every GetUrls and GetData call to the given duration.
+// Each retry gets its own timeout, which starts once the call is allowed to run: the waits for the rate limiter,
+// the crawl delay of the host, its quotas and its backoffs are not counted, so a crawl delay longer than the timeout
+// does not cancel requests before they are sent.
+// A URL returned with a positive Timeout by a URLDiscoverer uses that timeout instead.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.requestTimeout = d
+	}
+}
+
+// urlsOf converts plain URLs to URL descriptors without any specific setting.
+func urlsOf(urls []string) []URL {
+	out := make([]URL, len(urls))
+	for i, url := range urls {
+		out[i] = URL{URL: url}
+	}
+	return out
+}
+
+// paginate passes the next pages of a page through the OnPaginate hook, if any.
+func (s *Scraper[T]) paginate(from string, nextPages []URL) []URL {
+	if s.onPaginate == nil || len(nextPages) == 0 {
+		return nextPages
+	}
+
+	urls := make([]string, len(nextPages))
+	timeouts := make(map[string]time.Duration, len(nextPages))
+	for i, page := range nextPages {
+		urls[i] = page.URL
+		timeouts[page.URL] = page.Timeout
+	}
+
+	urls = s.onPaginate(from, urls)
+	pages := make([]URL, len(urls))
+	for i, url := range urls {
+		pages[i] = URL{URL: url, Timeout: timeouts[url]}
+	}
+	return pages
+}
+
+// discover retrieves the item URLs and next pages of a page, through DiscoverURLs when the scraper implements URLDiscoverer.
+func discover[T any](ctx context.Context, scraper IScraper[T], url string) ([]URL, []URL, error) {
+	if d, ok := scraper.(URLDiscoverer); ok {
+		return d.DiscoverURLs(ctx, url)
+	}
+
+	urls, nextPages, err := scraper.GetUrls(ctx, url)
+	return urlsOf(urls), urlsOf(nextPages), err
+}
+
+// requestContext returns the context of a single request for the work, bounded by its timeout, and the recorder of its response.
+// It carries the CrawlHandle of the work's strategy and, for a start page, the strategy's Request.
+func (s *Scraper[T]) requestContext(ctx context.Context, w Work) (context.Context, *recorder, context.CancelFunc) {
+	rec := newRecorder(s.clock, &s.counters, s.strategyStats[w.Strategy])
+	rec.onRecord = func(info ResponseInfo) {
+		s.checkBytes()
+		if s.visitCanonical(w, info) && w.Kind == ItemWork {
+			rec.duplicate()
+		}
+	}
+	ctx = context.WithValue(ctx, handleKey{}, s.handle(s.runCtx, w))
+	ctx = context.WithValue(ctx, recorderKey{}, rec)
+	ctx = s.withRequest(ctx, w)
+
+	timeout := s.requestTimeout
+	if w.Timeout > 0 {
+		timeout = w.Timeout
+	}
+	if timeout <= 0 {
+		return ctx, rec, func() {}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, rec, cancel
+}
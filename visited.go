@@ -0,0 +1,189 @@
+package scrapify
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// VisitedStore keeps track of the URLs that have already been scraped so the same URL is never processed twice.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type VisitedStore interface {
+	// Visit marks the URL as visited and reports whether it was newly added.
+	// It returns false when the URL had already been visited.
+	Visit(url string) bool
+
+	// Visited reports whether the URL has already been marked as visited.
+	Visited(url string) bool
+}
+
+// ListableVisitedStore is a VisitedStore that can enumerate its contents.
+// It is required by features that persist the visited set, such as checkpointing.
+type ListableVisitedStore interface {
+	VisitedStore
+
+	// URLs returns every visited URL, in no particular order.
+	URLs() []string
+}
+
+// MemoryVisitedStore is the default VisitedStore, an in-memory set guarded by a single mutex.
+type MemoryVisitedStore struct {
+	mu   sync.Mutex          // Guards the urls map.
+	urls map[string]struct{} // Set of visited URLs.
+}
+
+// NewMemoryVisitedStore creates an empty MemoryVisitedStore.
+func NewMemoryVisitedStore() *MemoryVisitedStore {
+	return &MemoryVisitedStore{urls: make(map[string]struct{})}
+}
+
+// Visit marks the URL as visited and reports whether it was newly added.
+func (m *MemoryVisitedStore) Visit(url string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.urls[url]; ok {
+		return false
+	}
+	m.urls[url] = struct{}{}
+	return true
+}
+
+// Visited reports whether the URL has already been marked as visited.
+func (m *MemoryVisitedStore) Visited(url string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.urls[url]
+	return ok
+}
+
+// URLs returns every visited URL, in no particular order.
+func (m *MemoryVisitedStore) URLs() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	urls := make([]string, 0, len(m.urls))
+	for url := range m.urls {
+		urls = append(urls, url)
+	}
+	return urls
+}
+
+// DefaultShardCount is the number of shards used by NewShardedVisitedStore when a non-positive count is given.
+const DefaultShardCount = 256
+
+// ShardedVisitedStore is a VisitedStore that spreads URLs across independently locked shards.
+// URLs are assigned to a shard by their FNV-1a hash, so concurrent workers rarely contend for the same lock.
+// It is meant for crawls with a very large number of concurrent workers, where a single mutex becomes a bottleneck.
+type ShardedVisitedStore struct {
+	shards []*MemoryVisitedStore // Independent sets, each with its own lock.
+}
+
+// NewShardedVisitedStore creates a ShardedVisitedStore with the given number of shards.
+// If shards is zero or negative, DefaultShardCount is used.
+func NewShardedVisitedStore(shards int) *ShardedVisitedStore {
+	if shards <= 0 {
+		shards = DefaultShardCount
+	}
+
+	s := &ShardedVisitedStore{shards: make([]*MemoryVisitedStore, shards)}
+	for i := range s.shards {
+		s.shards[i] = NewMemoryVisitedStore()
+	}
+	return s
+}
+
+// shard returns the shard responsible for the given URL.
+func (s *ShardedVisitedStore) shard(url string) *MemoryVisitedStore {
+	h := fnv.New32a()
+	h.Write([]byte(url))
+	return s.shards[h.Sum32()%uint32(len(s.shards))]
+}
+
+// Visit marks the URL as visited and reports whether it was newly added.
+func (s *ShardedVisitedStore) Visit(url string) bool {
+	return s.shard(url).Visit(url)
+}
+
+// Visited reports whether the URL has already been marked as visited.
+func (s *ShardedVisitedStore) Visited(url string) bool {
+	return s.shard(url).Visited(url)
+}
+
+// URLs returns every visited URL, in no particular order.
+func (s *ShardedVisitedStore) URLs() []string {
+	var urls []string
+	for _, shard := range s.shards {
+		urls = append(urls, shard.URLs()...)
+	}
+	return urls
+}
+
+// TTLVisitedStore is a VisitedStore whose entries expire: a URL visited more than the TTL ago is treated as not visited,
+// so it is scraped again on a later pass. Share one store between the Scrapers of successive passes with WithVisitedStore
+// to recrawl stale URLs only, picking up updated content without clearing the whole visited set.
+type TTLVisitedStore struct {
+	ttl   time.Duration        // How long a visit lasts.
+	clock Clock                // Source of time.
+	mu    sync.Mutex           // Guards the urls map.
+	urls  map[string]time.Time // Time of the last visit of each URL.
+}
+
+// NewTTLVisitedStore creates an empty TTLVisitedStore whose entries expire after ttl, measured with clock (the system clock if nil).
+func NewTTLVisitedStore(ttl time.Duration, clock Clock) *TTLVisitedStore {
+	if clock == nil {
+		clock = systemClock{}
+	}
+	return &TTLVisitedStore{ttl: ttl, clock: clock, urls: make(map[string]time.Time)}
+}
+
+// Visit marks the URL as visited now and reports whether it was not visited or its visit had expired.
+func (t *TTLVisitedStore) Visit(url string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.clock.Now()
+	if at, ok := t.urls[url]; ok && now.Sub(at) < t.ttl {
+		return false
+	}
+	t.urls[url] = now
+	return true
+}
+
+// Visited reports whether the URL has been visited less than the TTL ago.
+func (t *TTLVisitedStore) Visited(url string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	at, ok := t.urls[url]
+	return ok && t.clock.Now().Sub(at) < t.ttl
+}
+
+// URLs returns every URL whose visit has not expired, in no particular order.
+func (t *TTLVisitedStore) URLs() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.clock.Now()
+	urls := make([]string, 0, len(t.urls))
+	for url, at := range t.urls {
+		if now.Sub(at) < t.ttl {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// Purge forgets the expired visits, to reclaim their memory in long-running services.
+func (t *TTLVisitedStore) Purge() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.clock.Now()
+	for url, at := range t.urls {
+		if now.Sub(at) >= t.ttl {
+			delete(t.urls, url)
+		}
+	}
+}
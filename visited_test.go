@@ -0,0 +1,60 @@
+package scrapify_test
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ricardocastanho/scrapify"
+)
+
+func TestShardedVisitedStoreVisitsOnce(t *testing.T) {
+	store := scrapify.NewShardedVisitedStore(16)
+
+	var added atomic.Int64
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 1000 {
+				if store.Visit("https://example.com/" + strconv.Itoa(i)) {
+					added.Add(1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if added.Load() != 1000 {
+		t.Errorf("%d URLs reported as new, want 1000", added.Load())
+	}
+	if !store.Visited("https://example.com/999") || store.Visited("https://example.com/1000") {
+		t.Error("Visited does not reflect the visited URLs")
+	}
+}
+
+// benchmarkVisitedStore runs Visit and Visited in parallel from many goroutines, alternating new and known URLs.
+// Contention, and so the gain of sharding, only shows on machines with many CPUs: compare with -cpu 1,8,32.
+func benchmarkVisitedStore(b *testing.B, store scrapify.VisitedStore) {
+	var next atomic.Int64
+	b.SetParallelism(64)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			url := "https://example.com/item/" + strconv.FormatInt(next.Add(1), 10)
+			store.Visit(url)
+			store.Visited(url)
+		}
+	})
+}
+
+func BenchmarkVisitedStore(b *testing.B) {
+	b.Run("single-mutex", func(b *testing.B) {
+		benchmarkVisitedStore(b, scrapify.NewMemoryVisitedStore())
+	})
+	b.Run("sharded", func(b *testing.B) {
+		benchmarkVisitedStore(b, scrapify.NewShardedVisitedStore(256))
+	})
+}